@@ -0,0 +1,152 @@
+package zerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// BodyPolicy specifies how request and response bodies are logged
+type BodyPolicy struct {
+	// MaxSize is the maximum number of bytes of a body that are logged.
+	// Longer bodies are truncated. If MaxSize is 0, bodies are not logged
+	MaxSize int
+
+	// OmitContentTypes is a list of media types whose bodies are replaced by a summary.
+	// A type ending with "/" matches all subtypes, e.g. "image/"
+	OmitContentTypes []string
+}
+
+// DefaultBodyPolicy is the policy used when logging bodies, unless another policy has been specified.
+// It should only be modified during initialization
+var DefaultBodyPolicy = &BodyPolicy{
+	MaxSize: 16 << 10,
+	OmitContentTypes: []string{
+		"multipart/",
+		"image/",
+		"audio/",
+		"video/",
+		"font/",
+		"application/octet-stream",
+		"application/pdf",
+		"application/zip",
+		"application/gzip",
+		"application/x-protobuf",
+		"application/grpc",
+	},
+}
+
+// bodyPolicyOrDefault returns 'p', or DefaultBodyPolicy if 'p' is nil
+func bodyPolicyOrDefault(p *BodyPolicy) *BodyPolicy {
+	if p == nil {
+		return DefaultBodyPolicy
+	}
+	return p
+}
+
+// omit reports whether bodies with the media type 'mediaType' should be replaced by a summary
+func (p *BodyPolicy) omit(mediaType string) bool {
+	for _, t := range p.OmitContentTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody reads at most MaxSize+1 bytes from 'r', so that preview can detect if the body is longer than MaxSize.
+// If reading fails, the data read before the error is returned together with the error
+func (p *BodyPolicy) readBody(r io.Reader) ([]byte, error) {
+	return ioutil.ReadAll(io.LimitReader(r, int64(p.MaxSize)+1))
+}
+
+// preview returns the part of 'data', as returned by readBody, that should be logged.
+// The returned boolean is true if the body is longer than MaxSize
+func (p *BodyPolicy) preview(data []byte) ([]byte, bool) {
+	if len(data) > p.MaxSize {
+		return data[:p.MaxSize], true
+	}
+	return data, false
+}
+
+// isText reports whether 'body' looks like text, i.e. is valid UTF-8.
+// If the body was truncated, an incomplete rune at the end is ignored
+func isText(body []byte, truncated bool) bool {
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	}
+	return utf8.Valid(body)
+}
+
+// marshalBody encodes 'body' as the field 'key'.
+// Bodies that are omitted by the policy, or that aren't text, are replaced by a summary.
+// Complete JSON and form bodies are encoded as objects, and other bodies as strings.
+// 'size' is the total size of the body, or -1 if unknown
+func marshalBody(enc zapcore.ObjectEncoder, key string, contentType string, body []byte, truncated bool, size int64,
+	policy *BodyPolicy, redaction *RedactionPolicy) error {
+	if !truncated {
+		size = int64(len(body))
+	} else if size <= int64(len(body)) {
+		size = -1
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+
+	if policy.omit(mediaType) || !isText(body, truncated) {
+		summary := "[omitted body"
+		if mediaType != "" {
+			summary += " of type " + mediaType
+		}
+		if size >= 0 {
+			summary += fmt.Sprintf(", %d bytes", size)
+		}
+		enc.AddString(key, summary+"]")
+		return nil
+	}
+
+	if truncated {
+		marker := "... [truncated]"
+		if size >= 0 {
+			marker = fmt.Sprintf("... [truncated, %d bytes total]", size)
+		}
+		enc.AddString(key, string(body)+marker)
+		return nil
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if json.Valid(body) {
+			return enc.AddReflected(key, json.RawMessage(body))
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return enc.AddObject(key, redactedValues{values, redaction})
+		}
+	}
+
+	enc.AddByteString(key, body)
+	return nil
+}
+
+// redactedValues is a wrapper around url.Values that implements zapcore.ObjectMarshaler with a specific policy
+type redactedValues struct {
+	values url.Values
+	policy *RedactionPolicy
+}
+
+// MarshalLogObject encodes url values with a zapcore.ObjectEncoder
+func (v redactedValues) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return marshalURLValues(enc, v.values, v.policy)
+}
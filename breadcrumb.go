@@ -0,0 +1,105 @@
+package zerr
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// breadcrumb records where an error was wrapped
+type breadcrumb struct {
+	pc uintptr
+	op string
+}
+
+// callerPC returns the program counter of the caller, skipping the first 'skip' levels.
+// If skip is 0, the function calling callerPC is returned
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:])
+	return pcs[0]
+}
+
+// breadcrumbArray is a list of breadcrumbs that implements zapcore.ArrayMarshaler
+type breadcrumbArray struct {
+	crumbs []*breadcrumb
+	config *Config
+}
+
+// MarshalLogArray encodes each breadcrumb as an object with the function, the location and the operation name
+func (a breadcrumbArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, c := range a.crumbs {
+		frame, _ := runtime.CallersFrames([]uintptr{c.pc}).Next()
+		file := frame.File
+		if a.config.TrimPaths {
+			file = packageName(frame.Function) + "/" + path.Base(file)
+		}
+
+		err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("function", frame.Function)
+			enc.AddString("caller", file+":"+strconv.Itoa(frame.Line))
+			if c.op != "" {
+				enc.AddString("op", c.op)
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithOp creates a new Error instance, which records the name of the operation that failed
+// together with the location of the caller. The operation is included in the trace,
+// regardless of whether breadcrumbs are enabled in the configuration
+func (e *Error) WithOp(op string) *Error {
+	newErr := e.with(1, nil)
+	newErr.crumb = &breadcrumb{pc: callerPC(1), op: op}
+	return newErr
+}
+
+// traceField returns a field containing the breadcrumbs of all errors in the chain,
+// ordered from where the error was created to where it was last wrapped.
+// The returned boolean is false if no breadcrumbs are available
+func traceField(err error) (zap.Field, bool) {
+	var crumbs []*breadcrumb
+	var config *Config
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			if e == nil {
+				err = nil
+				continue
+			}
+			if config == nil {
+				config = e.conf()
+			}
+			if e.crumb != nil {
+				crumbs = append(crumbs, e.crumb)
+			}
+			if e.view != nil {
+				// The breadcrumbs below a view are included in its fields
+				err = nil
+				continue
+			}
+			err = e.err
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			err = nil
+		}
+	}
+	if len(crumbs) == 0 {
+		return zap.Field{}, false
+	}
+
+	// The chain is walked from the outermost error, so the order is reversed
+	for i, j := 0, len(crumbs)-1; i < j; i, j = i+1, j-1 {
+		crumbs[i], crumbs[j] = crumbs[j], crumbs[i]
+	}
+	return zap.Array(config.traceKey(), breadcrumbArray{crumbs: crumbs, config: config}), true
+}
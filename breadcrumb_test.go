@@ -0,0 +1,68 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// encodeTrace returns the encoded breadcrumbs of 'err'
+func encodeTrace(t *testing.T, err error) []interface{} {
+	f, ok := traceField(err)
+	require.True(t, ok)
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return enc.Fields["trace"].([]interface{})
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	config := NewConfig()
+	config.Breadcrumbs = true
+
+	// When
+	// breadcrumbs are disabled
+	// Then
+	// no trace is added
+	_, ok := traceField(Wrap(errors.New("test")).WithInt("intfield", 1))
+	require.False(t, ok)
+
+	// When
+	// an error is wrapped at multiple levels with breadcrumbs enabled
+	inner := func() error { return config.Wrap(errors.New("test")) }
+	middle := func() error { return fmt.Errorf("middle: %w", inner()) }
+	outer := func() error { return config.Wrap(middle()).WithString("stringfield", "abc").WithOp("outer") }
+	err := outer()
+
+	// Then
+	// each layer should be recorded, from the innermost to the outermost
+	// (Wrap in inner, and Wrap, WithString and WithOp in outer)
+	trace := encodeTrace(t, err)
+	require.Len(t, trace, 4)
+
+	first := trace[0].(map[string]interface{})
+	require.True(t, strings.HasPrefix(first["function"].(string), "github.com/yzzyx/zerr.TestBreadcrumbs.func1"))
+	require.Contains(t, first["caller"], "breadcrumb_test.go:")
+	require.NotContains(t, first, "op")
+
+	last := trace[3].(map[string]interface{})
+	require.True(t, strings.HasPrefix(last["function"].(string), "github.com/yzzyx/zerr.TestBreadcrumbs.func3"))
+	require.Equal(t, "outer", last["op"])
+
+	// And
+	// the trace should be included in the fields
+	fields := Fields(err)
+	require.Equal(t, "trace", fields[len(fields)-1].Key)
+
+	// When
+	// an operation is added without breadcrumbs enabled
+	trace = encodeTrace(t, WrapNoStack(errors.New("test")).WithOp("load"))
+
+	// Then
+	// only the operation is recorded
+	require.Len(t, trace, 1)
+	require.Equal(t, "load", trace[0].(map[string]interface{})["op"])
+}
@@ -0,0 +1,109 @@
+package zerr
+
+import (
+	"go.uber.org/zap"
+)
+
+// Code is used to classify errors, so that callers can decide how to handle
+// them without having to inspect the error message.
+// Code implements the error interface, which allows the codes to be used
+// as sentinel errors:
+//
+//	if errors.Is(err, zerr.CodeNotFound) { ... }
+type Code string
+
+// Predefined error codes
+const (
+	CodeUnknown          Code = ""
+	CodeInvalidArgument  Code = "invalid_argument"
+	CodeNotFound         Code = "not_found"
+	CodeConflict         Code = "conflict"
+	CodePermissionDenied Code = "permission_denied"
+	CodeUnauthenticated  Code = "unauthenticated"
+	CodeUnavailable      Code = "unavailable"
+	CodeInternal         Code = "internal"
+)
+
+// Error makes Code implement the standard error interface
+func (c Code) Error() string {
+	return string(c)
+}
+
+// Is reports whether this error has been assigned the code 'target'.
+// This allows errors.Is to be used with the predefined codes.
+// Since errors.Is checks every error in the chain, it matches all codes that have been
+// assigned to the error, also codes that have been overridden by an outer layer.
+// Use GetCode to get the code that is effective, i.e. the outermost one
+func (e *Error) Is(target error) bool {
+	c, ok := target.(Code)
+	return ok && c != CodeUnknown && e.code == c
+}
+
+// WithCode creates a new Error instance, with the error code set
+func (e *Error) WithCode(c Code) *Error {
+	newErr := e.with(1, nil)
+	newErr.code = c
+	return newErr
+}
+
+// WrapCode adds an error code and zap fields to an error
+func WrapCode(err error, c Code, fields ...zap.Field) *Error {
+	e := wrapWithStack(1, err, fields...)
+	return &Error{
+		err:      e,
+		code:     c,
+		config:   e.config,
+		hasStack: e.hasStack,
+	}
+}
+
+// GetCode returns the code of the outermost error in the chain that has a code set,
+// or CodeUnknown if no code is available
+func GetCode(err error) Code {
+	return findCode(err, true)
+}
+
+// findCode returns the code of the outermost error in the chain that has a code set.
+// If 'throughViews' is false, the search stops at errors created by Without, Only or Namespaced,
+// since their fields, including the code, are added separately
+func findCode(err error, throughViews bool) Code {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			if e == nil {
+				return CodeUnknown
+			}
+			if e.code != CodeUnknown {
+				return e.code
+			}
+			if e.view != nil && !throughViews {
+				return CodeUnknown
+			}
+			err = e.err
+		case *multiError:
+			// Joined errors keep their codes separate
+			return CodeUnknown
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				if c := findCode(child, throughViews); c != CodeUnknown {
+					return c
+				}
+			}
+			return CodeUnknown
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return CodeUnknown
+		}
+	}
+	return CodeUnknown
+}
+
+// codeField returns a field containing the code of 'err', and true if a code is available
+func codeField(err error) (zap.Field, bool) {
+	c := findCode(err, false)
+	if c == CodeUnknown {
+		return zap.Field{}, false
+	}
+	return zap.String("code", string(c)), true
+}
@@ -0,0 +1,59 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCode(t *testing.T) {
+	originalError := errors.New("original error")
+
+	// When
+	// we wrap an error without a code
+	err := error(Wrap(originalError))
+
+	// Then
+	// no code should be available
+	require.Equal(t, CodeUnknown, GetCode(err))
+	require.False(t, errors.Is(err, CodeUnknown))
+
+	// When
+	// we wrap an error with a code
+	err = WrapCode(originalError, CodeNotFound, zap.Int("intfield", 1))
+
+	// Then
+	// the code should be available, also through foreign wrappers
+	require.Equal(t, CodeNotFound, GetCode(err))
+	require.Equal(t, CodeNotFound, GetCode(fmt.Errorf("context: %w", err)))
+	require.True(t, errors.Is(err, CodeNotFound))
+	require.False(t, errors.Is(err, CodeConflict))
+	require.True(t, errors.Is(err, originalError))
+
+	// And
+	// the code should be added as a field, along with the int field and stacktrace
+	fields := Fields(err)
+	require.Len(t, fields, 3)
+	require.Equal(t, "code", fields[2].Key)
+	require.Equal(t, "not_found", fields[2].String)
+
+	// When
+	// the code is overridden by an outer layer
+	err = Wrap(err).WithCode(CodeUnavailable)
+
+	// Then
+	// the outermost code should be returned, and only one code field should be added
+	require.Equal(t, CodeUnavailable, GetCode(err))
+	fields = Fields(err)
+	require.Len(t, fields, 3)
+	require.Equal(t, "unavailable", fields[2].String)
+
+	// And
+	// errors.Is should match all codes in the chain, including the overridden one
+	require.True(t, errors.Is(err, CodeUnavailable))
+	require.True(t, errors.Is(err, CodeNotFound))
+	require.False(t, errors.Is(err, CodeConflict))
+}
@@ -0,0 +1,162 @@
+package zerr
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// defaultMaxFrames is the default maximum number of frames in a stacktrace
+const defaultMaxFrames = 64
+
+// Config controls how errors are wrapped, and how stacktraces are logged.
+// The package-level functions Wrap and Sugar use the global configuration, which
+// can be replaced with SetDefaultConfig.
+// Libraries that need a specific behaviour should use their own Config instead
+// of modifying the global one:
+//
+//	var errs = zerr.NewConfig()
+//	...
+//	return errs.Wrap(err, zap.String("key", key))
+//
+// A Config must not be modified after it has been used to wrap errors
+type Config struct {
+	// AddStacktrace specifies whether Wrap and Sugar add a stacktrace to errors
+	AddStacktrace bool
+
+	// StackKey is the key of the stacktrace field
+	StackKey string
+
+	// OriginKey is the key of the stacktrace captured where a goroutine was started, see CaptureOrigin
+	OriginKey string
+
+	// StackMode specifies how stacktraces are logged
+	StackMode StackMode
+
+	// MaxFrames is the maximum number of frames included in a stacktrace
+	MaxFrames int
+
+	// FrameFilter is called for each frame of a stacktrace when it is logged.
+	// If it returns false, the frame is omitted
+	FrameFilter func(f Frame) bool
+
+	// DropPackages is a list of package paths whose frames are omitted from stacktraces.
+	// Subpackages are omitted as well, e.g. "net/http" also matches "net/http/httptest"
+	DropPackages []string
+
+	// CollapseStdlib specifies that consecutive frames from the standard library are collapsed into one.
+	// Only the innermost frame of each sequence is kept, i.e. the frame closest to the code that wrapped the error.
+	// Frames are part of the standard library if their source file is located in GOROOT. For binaries built
+	// with -trimpath, packages outside the modules listed in the build info are used instead
+	CollapseStdlib bool
+
+	// Breadcrumbs specifies that each Wrap and WithX call records the location of its caller.
+	// The locations are logged as an array with the key TraceKey, showing the path the error took.
+	// This is a cheaper alternative to adding a stacktrace at each level
+	Breadcrumbs bool
+
+	// TraceKey is the key of the breadcrumb field
+	TraceKey string
+
+	// FieldConflict specifies how fields with the same key in different errors of a chain are handled.
+	// By default, all fields are kept
+	FieldConflict FieldConflict
+
+	// TrimPaths specifies that file paths are replaced by the package path and file name,
+	// e.g. "github.com/yzzyx/zerr/zerr.go", instead of the absolute path on the build machine
+	TrimPaths bool
+}
+
+// NewConfig returns a new Config with the default settings:
+// stacktraces are added as formatted strings with the key "stacktrace", with at most 64 frames,
+// and breadcrumbs are disabled
+func NewConfig() *Config {
+	return &Config{
+		AddStacktrace: true,
+		StackKey:      "stacktrace",
+		TraceKey:      "trace",
+		OriginKey:     "origin_stacktrace",
+		StackMode:     StackModeString,
+		MaxFrames:     defaultMaxFrames,
+	}
+}
+
+var defaultConfig atomic.Value
+
+func init() {
+	defaultConfig.Store(NewConfig())
+}
+
+// DefaultConfig returns the global configuration
+func DefaultConfig() *Config {
+	return defaultConfig.Load().(*Config)
+}
+
+// SetDefaultConfig replaces the global configuration, which is used by the package-level functions
+// such as Wrap and Sugar. If 'c' is nil, the default settings are restored.
+// Errors that have already been wrapped keep using the configuration they were wrapped with
+func SetDefaultConfig(c *Config) {
+	if c == nil {
+		c = NewConfig()
+	}
+	defaultConfig.Store(c)
+}
+
+// Wrap adds zap fields to an error, and a stacktrace if enabled by the configuration
+func (c *Config) Wrap(err error, fields ...zap.Field) *Error {
+	return c.wrapWithStack(1, err, fields...)
+}
+
+// Sugar is a sugared version of the 'Wrap' method.
+// See the package-level function Sugar for details
+func (c *Config) Sugar(err error, args ...interface{}) *Error {
+	return c.wrapWithStack(1, err, sugarFields(args...)...)
+}
+
+// stackKey returns the key of the stacktrace field
+func (c *Config) stackKey() string {
+	if c.StackKey == "" {
+		return "stacktrace"
+	}
+	return c.StackKey
+}
+
+// originKey returns the key of the origin stacktrace field
+func (c *Config) originKey() string {
+	if c.OriginKey == "" {
+		return "origin_stacktrace"
+	}
+	return c.OriginKey
+}
+
+// traceKey returns the key of the breadcrumb field
+func (c *Config) traceKey() string {
+	if c.TraceKey == "" {
+		return "trace"
+	}
+	return c.TraceKey
+}
+
+// filtersFrames reports whether any frames may be omitted from stacktraces
+func (c *Config) filtersFrames() bool {
+	return c.FrameFilter != nil || len(c.DropPackages) > 0 || c.CollapseStdlib
+}
+
+// dropPackage reports whether frames from the package 'pkg' should be omitted
+func (c *Config) dropPackage(pkg string) bool {
+	for _, p := range c.DropPackages {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFrames returns the maximum number of frames in a stacktrace
+func (c *Config) maxFrames() int {
+	if c.MaxFrames <= 0 {
+		return defaultMaxFrames
+	}
+	return c.MaxFrames
+}
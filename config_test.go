@@ -0,0 +1,121 @@
+package zerr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfig(t *testing.T) {
+	// When
+	// stacktraces are disabled
+	config := NewConfig()
+	config.AddStacktrace = false
+	e := config.Wrap(errors.New("test"), zap.Int("intfield", 1))
+
+	// Then
+	// no stacktrace should be added
+	require.Len(t, e.Fields(), 1)
+	require.Nil(t, e.StackFrames())
+
+	// When
+	// the stack key is changed, and the number of frames limited
+	config = NewConfig()
+	config.StackKey = "stack"
+	config.MaxFrames = 1
+	e = config.Sugar(errors.New("test"), "intfield", 1)
+
+	// Then
+	// the stacktrace should be added with the new key, and only contain one frame
+	fields := e.Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "stack", fields[1].Key)
+	frames := e.StackFrames()
+	require.Len(t, frames, 1)
+	require.Equal(t, "github.com/yzzyx/zerr.TestConfig", frames[0].Function)
+
+	// When
+	// a frame filter is used
+	config = NewConfig()
+	config.FrameFilter = func(f Frame) bool { return f.Package != "testing" }
+	e = config.Wrap(errors.New("test"))
+
+	// Then
+	// the filtered frames should be omitted
+	for _, f := range e.StackFrames() {
+		require.NotEqual(t, "testing", f.Package)
+	}
+	require.False(t, strings.Contains(e.stack.String(), "testing.tRunner"))
+
+	// When
+	// the global configuration is replaced
+	config = NewConfig()
+	config.AddStacktrace = false
+	SetDefaultConfig(config)
+	defer SetDefaultConfig(nil)
+
+	// Then
+	// Wrap should use the new configuration
+	require.Len(t, Wrap(errors.New("test"), zap.Int("intfield", 1)).Fields(), 1)
+
+	// And
+	// errors wrapped with another configuration are not affected
+	require.Len(t, e.Fields(), 1)
+	require.Equal(t, "stacktrace", e.Fields()[0].Key)
+}
+
+func TestFrameFiltering(t *testing.T) {
+	// capture returns the frames of an error wrapped in a new goroutine, started by 'testing'
+	capture := func(config *Config) []Frame {
+		ch := make(chan *Error)
+		go func() { ch <- config.Wrap(errors.New("test")) }()
+		return (<-ch).StackFrames()
+	}
+
+	// When
+	// stacktraces are captured without filters
+	frames := capture(NewConfig())
+
+	// Then
+	// the runtime frame at the end of the goroutine should be included
+	require.Equal(t, "runtime", frames[len(frames)-1].Package)
+
+	// When
+	// the runtime package is dropped
+	config := NewConfig()
+	config.DropPackages = []string{"runtime"}
+	frames = capture(config)
+
+	// Then
+	// no runtime frames should be included
+	for _, f := range frames {
+		require.NotEqual(t, "runtime", f.Package)
+	}
+
+	// When
+	// stdlib frames are collapsed
+	config = NewConfig()
+	config.CollapseStdlib = true
+	frames = Wrap(errors.New("test")).StackFrames()
+	collapsed := config.Wrap(errors.New("test")).StackFrames()
+
+	// Then
+	// the testing and runtime frames should be collapsed into one
+	require.Equal(t, "testing", frames[1].Package)
+	require.Equal(t, "runtime", frames[2].Package)
+	require.Len(t, collapsed, 2)
+	require.Equal(t, "testing", collapsed[1].Package)
+
+	// When
+	// paths are trimmed
+	config = NewConfig()
+	config.TrimPaths = true
+	frames = config.Wrap(errors.New("test")).StackFrames()
+
+	// Then
+	// the file should be relative to the package path
+	require.Equal(t, "github.com/yzzyx/zerr/config_test.go", frames[0].File)
+}
@@ -0,0 +1,132 @@
+package zerr
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldConflict specifies how fields with the same key in an error chain are handled.
+// This applies to fields added by the same or different errors, and to the fields added by zerr,
+// such as "code" and "stacktrace". The fields "code" and "trace" describe the chain as a whole,
+// and count as added by the outermost error
+type FieldConflict int
+
+const (
+	// ConflictKeepAll keeps all fields, which may result in duplicate keys when logged
+	ConflictKeepAll FieldConflict = iota
+
+	// ConflictInnermostWins keeps the field added closest to where the error was created.
+	// Within a single error, the first field is kept
+	ConflictInnermostWins
+
+	// ConflictOutermostWins keeps the field added last, i.e. furthest from where the error was created.
+	// Within a single error, the last field is kept
+	ConflictOutermostWins
+
+	// ConflictSuffix keeps all fields. The outermost field keeps its key, while the others
+	// get the index of their layer appended to their keys, e.g. "user_id_2".
+	// Layers are numbered from 0, starting with the outermost error
+	ConflictSuffix
+
+	// ConflictNamespace adds the fields of each layer as an object, with the index of the layer as key,
+	// e.g. "layer0", "layer1". Layers are numbered from 0, starting with the outermost error.
+	// Stacktraces and other fields added by zerr are not namespaced
+	ConflictNamespace
+)
+
+// flatten returns the fields of all layers as a single slice, followed by 'extra', resolving conflicts according to 'c'.
+// The fields in 'extra', such as the error code, are added by zerr for the chain as a whole, and are
+// treated as if they were added last
+func (c FieldConflict) flatten(layers []layer, extra []zap.Field) []zap.Field {
+	n := len(extra)
+	for i := range layers {
+		n += layers[i].size()
+	}
+	fields := make([]zap.Field, 0, n)
+
+	if c == ConflictKeepAll {
+		for i := range layers {
+			fields = append(fields, layers[i].fields...)
+			fields = layers[i].appendInternal(fields)
+		}
+		return append(fields, extra...)
+	}
+
+	if c == ConflictNamespace {
+		for i := range layers {
+			if len(layers[i].fields) > 0 {
+				fields = append(fields, zap.Object("layer"+strconv.Itoa(i), fieldList(layers[i].fields)))
+			}
+			fields = layers[i].appendInternal(fields)
+		}
+		return append(fields, extra...)
+	}
+
+	// starts[i] is the index of the first field of layer i
+	starts := make([]int, len(layers)+1)
+	for i := range layers {
+		starts[i] = len(fields)
+		fields = append(fields, layers[i].fields...)
+		fields = layers[i].appendInternal(fields)
+	}
+	starts[len(layers)] = len(fields)
+	fields = append(fields, extra...)
+
+	// Find the field each key should be taken from. Fields are visited in the order they were added,
+	// i.e. from the innermost layer and outwards, and in order within each layer
+	owner := make(map[string]int)
+	count := make(map[string]int)
+	visit := func(from, to int) {
+		for i := from; i < to; i++ {
+			f := fields[i]
+			if !hasKey(f) {
+				continue
+			}
+			count[f.Key]++
+			if _, ok := owner[f.Key]; !ok || c != ConflictInnermostWins {
+				owner[f.Key] = i
+			}
+		}
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		visit(starts[i], starts[i+1])
+	}
+	visit(starts[len(layers)], len(fields))
+
+	// The fields are filtered in place, since no field is moved to a later position
+	result := fields[:0]
+	layerIdx := 0
+	for i, f := range fields {
+		for layerIdx < len(layers) && i >= starts[layerIdx+1] {
+			layerIdx++
+		}
+		switch {
+		case !hasKey(f) || count[f.Key] == 1 || owner[f.Key] == i:
+		case c == ConflictSuffix:
+			f.Key = suffixKey(f.Key, layerIdx, count)
+		default:
+			continue
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// suffixKey returns 'key' with the index of its layer appended, and marks the new key as used in 'count'.
+// If the key is already used, e.g. by another field in the same layer, a counter is appended as well
+func suffixKey(key string, layerIdx int, count map[string]int) string {
+	suffixed := key + "_" + strconv.Itoa(layerIdx)
+	newKey := suffixed
+	for n := 2; count[newKey] > 0; n++ {
+		newKey = suffixed + "_" + strconv.Itoa(n)
+	}
+	count[newKey]++
+	return newKey
+}
+
+// hasKey reports whether the key of 'f' is used when encoding the field
+func hasKey(f zap.Field) bool {
+	return f.Type != zapcore.SkipType && f.Type != zapcore.InlineMarshalerType
+}
@@ -0,0 +1,108 @@
+package zerr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFieldConflict(t *testing.T) {
+	newError := func(policy FieldConflict) *Error {
+		config := NewConfig()
+		config.AddStacktrace = false
+		config.FieldConflict = policy
+
+		e := config.Wrap(errors.New("test"), zap.Int("user_id", 1), zap.String("inner", "a"))
+		return config.Wrap(e, zap.Int("user_id", 2)).WithInt("user_id", 3)
+	}
+
+	// encode returns the keys and values of 'fields', in order
+	encode := func(fields []zap.Field) ([]string, []interface{}) {
+		var keys []string
+		var values []interface{}
+		for _, f := range fields {
+			enc := zapcore.NewMapObjectEncoder()
+			f.AddTo(enc)
+			keys = append(keys, f.Key)
+			values = append(values, enc.Fields[f.Key])
+		}
+		return keys, values
+	}
+
+	// When
+	// all fields are kept
+	keys, values := encode(newError(ConflictKeepAll).Fields())
+
+	// Then
+	// duplicate keys are returned, outermost first
+	require.Equal(t, []string{"user_id", "user_id", "user_id", "inner"}, keys)
+	require.Equal(t, []interface{}{int64(3), int64(2), int64(1), "a"}, values)
+
+	// When
+	// the innermost field wins
+	keys, values = encode(newError(ConflictInnermostWins).Fields())
+
+	// Then
+	// only the innermost value is kept
+	require.Equal(t, []string{"user_id", "inner"}, keys)
+	require.Equal(t, []interface{}{int64(1), "a"}, values)
+
+	// When
+	// the outermost field wins
+	keys, values = encode(newError(ConflictOutermostWins).Fields())
+
+	// Then
+	// only the outermost value is kept
+	require.Equal(t, []string{"user_id", "inner"}, keys)
+	require.Equal(t, []interface{}{int64(3), "a"}, values)
+
+	// When
+	// conflicting keys are suffixed
+	keys, values = encode(newError(ConflictSuffix).Fields())
+
+	// Then
+	// all values are kept, with the layer appended to all but the outermost
+	require.Equal(t, []string{"user_id", "user_id_1", "user_id_2", "inner"}, keys)
+	require.Equal(t, []interface{}{int64(3), int64(2), int64(1), "a"}, values)
+
+	// When
+	// each layer is namespaced
+	keys, values = encode(newError(ConflictNamespace).Fields())
+
+	// Then
+	// the fields of each layer are added as an object
+	require.Equal(t, []string{"layer0", "layer1", "layer2"}, keys)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"user_id": int64(3)},
+		map[string]interface{}{"user_id": int64(2)},
+		map[string]interface{}{"user_id": int64(1), "inner": "a"},
+	}, values)
+
+	// When
+	// the same key is used twice in a single error, and a field conflicts with the code added by zerr
+	newDuplicates := func(policy FieldConflict) *Error {
+		config := NewConfig()
+		config.AddStacktrace = false
+		config.FieldConflict = policy
+
+		e := config.Wrap(errors.New("test"), zap.Int("a", 1), zap.Int("a", 2), zap.String("code", "user"))
+		return config.Wrap(e.WithCode(CodeNotFound), zap.Int("a", 3))
+	}
+
+	// Then
+	// the policy should apply within the error, and to the code
+	keys, values = encode(newDuplicates(ConflictInnermostWins).Fields())
+	require.Equal(t, []string{"a", "code"}, keys)
+	require.Equal(t, []interface{}{int64(1), "user"}, values)
+
+	keys, values = encode(newDuplicates(ConflictOutermostWins).Fields())
+	require.Equal(t, []string{"a", "code"}, keys)
+	require.Equal(t, []interface{}{int64(3), "not_found"}, values)
+
+	keys, values = encode(newDuplicates(ConflictSuffix).Fields())
+	require.Equal(t, []string{"a", "a_1", "a_1_2", "code_1", "code"}, keys)
+	require.Equal(t, []interface{}{int64(3), int64(1), int64(2), "user", "not_found"}, values)
+}
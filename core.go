@@ -0,0 +1,175 @@
+package zerr
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CoreOption configures a core created by NewCore
+type CoreOption func(*core)
+
+// CoreNamespace specifies that the fields extracted from errors should be added
+// as an object with the key 'namespace', instead of being added to the entry directly
+func CoreNamespace(namespace string) CoreOption {
+	return func(c *core) {
+		c.namespace = namespace
+	}
+}
+
+// core is a zapcore.Core that adds the fields of any zerr errors to each entry
+type core struct {
+	zapcore.Core
+	namespace string
+}
+
+// NewCore wraps a zapcore.Core, so that the fields of errors logged with zap.Error or zap.NamedError
+// are added to the entry, if any *Error is found in the error chain.
+// Fields with keys that are already present in the entry are skipped.
+// This allows existing code that logs errors with plain zap to include the fields, e.g.
+//
+//	logger = logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+//	    return zerr.NewCore(c)
+//	}))
+func NewCore(c zapcore.Core, opts ...CoreOption) zapcore.Core {
+	zc := &core{Core: c}
+	for _, opt := range opts {
+		opt(zc)
+	}
+	return zc
+}
+
+// With adds structured context to the core
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		Core:      c.Core.With(c.expand(fields)),
+		namespace: c.namespace,
+	}
+}
+
+// Check lets the wrapped core determine whether the entry should be logged, so that the levels of
+// each core in a tee and sampling still apply. If any core accepts the entry, this core is added,
+// and adds the fields of any errors before passing the entry on to the accepting cores
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked := c.Core.Check(ent, nil)
+	if checked == nil {
+		return ce
+	}
+	return ce.AddCore(ent, &checkedCore{core: c, ce: checked})
+}
+
+// Write adds the fields of any errors, and writes the entry to the wrapped core
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.expand(fields))
+}
+
+// checkedCore writes entries to the cores that were selected by the wrapped core
+type checkedCore struct {
+	*core
+	ce *zapcore.CheckedEntry
+}
+
+// Write adds the fields of any errors, and writes the entry to the selected cores
+func (c *checkedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// CheckedEntry reports write errors to its ErrorOutput, so they are collected and returned instead
+	var out writeErrors
+	c.ce.ErrorOutput = &out
+	c.ce.Write(c.expand(fields)...)
+	return out.err
+}
+
+// writeErrors is a zapcore.WriteSyncer that records the first error reported by a CheckedEntry
+type writeErrors struct {
+	err error
+}
+
+// Write records 'p' as an error, unless an error has already been recorded
+func (w *writeErrors) Write(p []byte) (int, error) {
+	if w.err == nil {
+		w.err = errors.New(strings.TrimSpace(string(p)))
+	}
+	return len(p), nil
+}
+
+// Sync does nothing, since the errors are kept in memory
+func (w *writeErrors) Sync() error {
+	return nil
+}
+
+// expand returns 'fields' with the fields of all zerr errors added.
+// The errors themselves are logged with their message only, since zap would otherwise add
+// all fields a second time as the text field "errorVerbose"
+func (c *core) expand(fields []zapcore.Field) []zapcore.Field {
+	var extra []zapcore.Field
+	expanded := false
+	for _, f := range fields {
+		if err, ok := zerrError(f); ok {
+			extra = append(extra, Fields(err)...)
+			expanded = true
+		}
+	}
+	if !expanded {
+		return fields
+	}
+
+	seen := make(map[string]bool, len(fields)+len(extra))
+	if c.namespace == "" {
+		for _, f := range fields {
+			seen[f.Key] = true
+		}
+	}
+
+	unique := extra[:0]
+	for _, f := range extra {
+		if !seen[f.Key] {
+			seen[f.Key] = true
+			unique = append(unique, f)
+		}
+	}
+
+	result := make([]zapcore.Field, 0, len(fields)+len(unique)+1)
+	for _, f := range fields {
+		if err, ok := zerrError(f); ok {
+			f = zap.NamedError(f.Key, messageError{err})
+		}
+		result = append(result, f)
+	}
+	if c.namespace != "" {
+		if len(unique) == 0 {
+			return result
+		}
+		return append(result, zap.Object(c.namespace, fieldList(unique)))
+	}
+	return append(result, unique...)
+}
+
+// zerrError returns the error of 'f', if 'f' is an error field and an *Error is found in the error chain
+func zerrError(f zapcore.Field) (error, bool) {
+	if f.Type != zapcore.ErrorType {
+		return nil, false
+	}
+	err, ok := f.Interface.(error)
+	if !ok {
+		return nil, false
+	}
+	var e *Error
+	return err, errors.As(err, &e)
+}
+
+// messageError hides all methods of an error except Error, so that zap only logs the message
+type messageError struct {
+	error
+}
+
+// fieldList is a list of fields that implements zapcore.ObjectMarshaler
+type fieldList []zapcore.Field
+
+// MarshalLogObject adds all fields to the encoder
+func (l fieldList) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range l {
+		f.AddTo(enc)
+	}
+	return nil
+}
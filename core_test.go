@@ -0,0 +1,101 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCore(t *testing.T) {
+	err := fmt.Errorf("context: %w", WrapNoStack(errors.New("test"), zap.Int("intfield", 1), zap.String("user", "inner")))
+
+	// When
+	// an error is logged with plain zap through the core
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(NewCore(obs))
+	logger.Error("failed", zap.Error(err), zap.String("user", "outer"), zap.Error(errors.New("plain")))
+
+	// Then
+	// the fields of the error should be added, except for duplicate keys
+	entry := logs.TakeAll()[0]
+	require.Len(t, entry.Context, 4)
+	require.Equal(t, "intfield", entry.Context[3].Key)
+	require.Equal(t, "outer", entry.ContextMap()["user"])
+
+	// When
+	// an *Error is logged directly
+	logger.Error("failed", zap.Error(Wrap(errors.New("direct"), zap.Int("intfield", 1))))
+
+	// Then
+	// the error should only be logged with its message, since the fields are already added
+	entry = logs.TakeAll()[0]
+	enc := zapcore.NewMapObjectEncoder()
+	entry.Context[0].AddTo(enc)
+	require.Equal(t, map[string]interface{}{"error": "direct"}, enc.Fields)
+	require.Contains(t, entry.ContextMap(), "stacktrace")
+
+	// When
+	// the error is added with With()
+	logger.With(zap.Error(err)).Info("failed")
+
+	// Then
+	// the fields should be added as well
+	require.Equal(t, int64(1), logs.TakeAll()[0].ContextMap()["intfield"])
+
+	// When
+	// the core is created with a namespace
+	logger = zap.New(NewCore(obs, CoreNamespace("details")))
+	logger.Error("failed", zap.Error(err), zap.String("user", "outer"))
+
+	// Then
+	// the fields should be added as an object
+	fields := logs.TakeAll()[0].ContextMap()
+	require.Equal(t, "outer", fields["user"])
+	require.Equal(t, map[string]interface{}{"intfield": int64(1), "user": "inner"}, fields["details"])
+
+	// When
+	// the entry is below the level of the wrapped core
+	obs, logs = observer.New(zapcore.ErrorLevel)
+	zap.New(NewCore(obs)).Info("ignored", zap.Error(err))
+
+	// Then
+	// nothing is logged
+	require.Equal(t, 0, logs.Len())
+}
+
+func TestCoreCheck(t *testing.T) {
+	err := WrapNoStack(errors.New("test"), zap.Int("intfield", 1))
+
+	// When
+	// the core wraps a tee of cores with different levels
+	debugCore, debugLogs := observer.New(zap.DebugLevel)
+	errorCore, errorLogs := observer.New(zap.ErrorLevel)
+	logger := zap.New(NewCore(zapcore.NewTee(debugCore, errorCore)))
+	logger.Info("failed", zap.Error(err))
+
+	// Then
+	// only the cores accepting the entry should receive it, with the fields of the error
+	require.Equal(t, 0, errorLogs.Len())
+	entries := debugLogs.TakeAll()
+	require.Len(t, entries, 1)
+	require.Equal(t, int64(1), entries[0].ContextMap()["intfield"])
+
+	// When
+	// the core wraps a sampler, which only logs the first entry with the same message
+	obs, logs := observer.New(zap.DebugLevel)
+	logger = zap.New(NewCore(zapcore.NewSamplerWithOptions(obs, time.Minute, 1, 0)))
+	for i := 0; i < 3; i++ {
+		logger.Error("failed", zap.Error(err))
+	}
+
+	// Then
+	// sampling should still apply
+	require.Equal(t, 1, logs.Len())
+	require.Equal(t, int64(1), logs.All()[0].ContextMap()["intfield"])
+}
@@ -0,0 +1,86 @@
+package zerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// MarshalLogObject makes Error implement zapcore.ObjectMarshaler.
+// The error message is added with the key "error", together with all fields attached to the error.
+// This allows zap.Any and zap.Object to log the error with all of its fields, e.g.
+//
+//	logger.Error("request failed", zap.Any("cause", err))
+func (e *Error) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return errorObject{e}.MarshalLogObject(enc)
+}
+
+// Format makes Error implement fmt.Formatter.
+// %+v prints the error message followed by all fields attached to the error, one per line.
+// All other verbs and flags are applied to the error message, as if it was a string,
+// e.g. %s and %v print the message, %q prints a quoted message and %10s pads it.
+// When an Error is logged with zap.Error or zap.NamedError, zap uses %+v to add the fields as
+// the string field "errorVerbose". zap does not allow error fields to be encoded as objects,
+// so use zap.Any or zap.Object to log the error as a nested object, or NewCore to add the fields to the entry
+func (e *Error) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, e.Error())
+		writeFields(s, e.Fields())
+		return
+	}
+	fmt.Fprintf(s, formatString(s, verb), e.Error())
+}
+
+// formatString returns the directive that 's' and 'verb' were created from, e.g. "%-10s".
+// This is equivalent to fmt.FormatString, which is not available in all supported Go versions
+func formatString(s fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := s.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// writeFields writes each field as "key: value" on a separate line
+func writeFields(w io.Writer, fields []zapcore.Field) {
+	for _, f := range fields {
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+
+		// Fields like zap.Inline may add more than one key
+		keys := make([]string, 0, len(enc.Fields))
+		for k := range enc.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			switch v := enc.Fields[k].(type) {
+			case string:
+				fmt.Fprintf(w, "\n%s: %s", k, v)
+			default:
+				if data, err := json.Marshal(v); err == nil {
+					fmt.Fprintf(w, "\n%s: %s", k, data)
+				} else {
+					fmt.Fprintf(w, "\n%s: %v", k, v)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFormat(t *testing.T) {
+	err := WrapNoStack(errors.New("test"), zap.Int("intfield", 1), zap.Strings("strings", []string{"a", "b"}))
+
+	// When
+	// we format the error with %s, %v or %q
+	// Then
+	// only the message is printed
+	require.Equal(t, "test", fmt.Sprintf("%s", err))
+	require.Equal(t, "test", fmt.Sprintf("%v", err))
+	require.Equal(t, `"test"`, fmt.Sprintf("%q", err))
+
+	// When
+	// we format the error with other verbs, or with flags
+	// Then
+	// they are applied to the message, as for a string
+	require.Equal(t, "      test", fmt.Sprintf("%10s", err))
+	require.Equal(t, "test  |", fmt.Sprintf("%-6v|", err))
+	require.Equal(t, "te", fmt.Sprintf("%.2s", err))
+	require.Equal(t, "74657374", fmt.Sprintf("%x", err))
+	require.Equal(t, "%!d(string=test)", fmt.Sprintf("%d", err))
+
+	// When
+	// we format the error with %+v
+	// Then
+	// the fields are printed as well
+	require.Equal(t, "test\nintfield: 1\nstrings: [\"a\",\"b\"]", fmt.Sprintf("%+v", err))
+}
+
+func TestZapIntegration(t *testing.T) {
+	err := Wrap(errors.New("test"), zap.Int("intfield", 1))
+
+	// When
+	// we log the error with zap.Error
+	enc := zapcore.NewMapObjectEncoder()
+	zap.Error(err).AddTo(enc)
+
+	// Then
+	// the message and a verbose description with the fields should be added
+	require.Equal(t, "test", enc.Fields["error"])
+	verbose := enc.Fields["errorVerbose"].(string)
+	require.True(t, strings.HasPrefix(verbose, "test\nintfield: 1\nstacktrace: "))
+
+	// When
+	// we log the error with zap.Any
+	enc = zapcore.NewMapObjectEncoder()
+	zap.Any("cause", err).AddTo(enc)
+
+	// Then
+	// the error should be added as an object with the message and fields
+	obj := enc.Fields["cause"].(map[string]interface{})
+	require.Equal(t, "test", obj["error"])
+	require.Equal(t, int64(1), obj["intfield"])
+	require.Contains(t, obj, "stacktrace")
+}
@@ -0,0 +1,124 @@
+package zerr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// statusMapping maps errors matching 'target' to a HTTP status code
+type statusMapping struct {
+	target error
+	status int
+}
+
+var (
+	statusMu sync.RWMutex
+
+	// codeStatus maps error codes to HTTP status codes
+	codeStatus = map[Code]int{
+		CodeInvalidArgument:  http.StatusBadRequest,
+		CodeNotFound:         http.StatusNotFound,
+		CodeConflict:         http.StatusConflict,
+		CodePermissionDenied: http.StatusForbidden,
+		CodeUnauthenticated:  http.StatusUnauthorized,
+		CodeUnavailable:      http.StatusServiceUnavailable,
+		CodeInternal:         http.StatusInternalServerError,
+	}
+
+	// statusCodes maps HTTP status codes back to error codes, see codeForStatus.
+	// If more than one code is registered for a status, the first one is kept
+	statusCodes = reverseCodeStatus()
+
+	// errorStatus contains mappings registered with RegisterStatus
+	errorStatus []statusMapping
+)
+
+// reverseCodeStatus returns the default mapping from HTTP status codes to error codes
+func reverseCodeStatus() map[int]Code {
+	m := make(map[int]Code, len(codeStatus))
+	for c, status := range codeStatus {
+		m[status] = c
+	}
+	return m
+}
+
+// ErrorResponse is the JSON body written by WriteError
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  Code   `json:"code,omitempty"`
+}
+
+// RegisterStatus registers the HTTP status code to use for errors matching 'target', as reported by errors.Is.
+// If 'target' is a Code, the status code used for that code is replaced. The code is also used for
+// responses with that status, see Transport, unless another code is already registered for it.
+// Other mappings are checked in the order they were registered, and take precedence over error codes.
+//
+//	zerr.RegisterStatus(sql.ErrNoRows, http.StatusNotFound)
+func RegisterStatus(target error, status int) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	if c, ok := target.(Code); ok {
+		if old, ok := codeStatus[c]; ok && statusCodes[old] == c {
+			delete(statusCodes, old)
+		}
+		codeStatus[c] = status
+		if _, ok := statusCodes[status]; !ok {
+			statusCodes[status] = c
+		}
+		return
+	}
+	errorStatus = append(errorStatus, statusMapping{target: target, status: status})
+}
+
+// StatusCode returns the HTTP status code that corresponds to an error.
+// Mappings registered with RegisterStatus are checked first, followed by the error code.
+// If no status code can be found, http.StatusInternalServerError is returned
+func StatusCode(err error) int {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	for _, m := range errorStatus {
+		if errors.Is(err, m.target) {
+			return m.status
+		}
+	}
+
+	if status, ok := codeStatus[GetCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError writes a JSON error response for 'err' to 'w', with the status code returned by StatusCode.
+// The error is logged to 'logger' together with all of its fields and the request 'r',
+// but the fields are never included in the response.
+// For client errors (4xx), the error message is included in the response. For all other status codes
+// the standard status text is used instead, in order to not leak internal information
+func WriteError(w http.ResponseWriter, r *http.Request, logger *zap.Logger, err error) {
+	status := StatusCode(err)
+
+	e := wrapWithStack(1, err, zap.Int("status", status)).WithRequest(r)
+	if status >= 500 {
+		e.LogError(logger)
+	} else {
+		e.LogWarn(logger)
+	}
+
+	resp := ErrorResponse{
+		Error: http.StatusText(status),
+		Code:  GetCode(err),
+	}
+	if status >= 400 && status < 500 {
+		resp.Error = e.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
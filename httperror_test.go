@@ -0,0 +1,103 @@
+package zerr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// restoreStatus restores the status mappings when the test is done, since RegisterStatus changes global state
+func restoreStatus(t *testing.T) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	codes := make(map[Code]int, len(codeStatus))
+	for c, status := range codeStatus {
+		codes[c] = status
+	}
+	statuses := make(map[int]Code, len(statusCodes))
+	for status, c := range statusCodes {
+		statuses[status] = c
+	}
+	mappings := errorStatus
+
+	t.Cleanup(func() {
+		statusMu.Lock()
+		defer statusMu.Unlock()
+		codeStatus = codes
+		statusCodes = statuses
+		errorStatus = mappings
+	})
+}
+
+func TestStatusCode(t *testing.T) {
+	restoreStatus(t)
+
+	// When
+	// an error has no code
+	// Then
+	// 500 is returned
+	require.Equal(t, http.StatusInternalServerError, StatusCode(errors.New("test")))
+
+	// When
+	// an error has a code
+	// Then
+	// the corresponding status is returned
+	require.Equal(t, http.StatusNotFound, StatusCode(WrapCode(errors.New("test"), CodeNotFound)))
+
+	// When
+	// a mapping is registered for a specific error
+	errTeapot := errors.New("teapot")
+	RegisterStatus(errTeapot, http.StatusTeapot)
+
+	// Then
+	// the registered status is returned, regardless of code
+	require.Equal(t, http.StatusTeapot, StatusCode(WrapCode(errTeapot, CodeNotFound)))
+}
+
+func TestWriteError(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+
+	// When
+	// we write a client error
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://www.example.com/item/1", nil)
+	err := WrapCode(errors.New("item not found"), CodeNotFound, zap.String("secret", "internal"))
+	WriteError(w, r, logger, err)
+
+	// Then
+	// the response should contain the status, message and code, but not the fields
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	require.NotContains(t, w.Body.String(), "secret")
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, ErrorResponse{Error: "item not found", Code: CodeNotFound}, resp)
+
+	// And
+	// the error should be logged with all fields and the request
+	require.Equal(t, 1, logs.Len())
+	logged := logs.TakeAll()[0].ContextMap()
+	require.Equal(t, "internal", logged["secret"])
+	require.Equal(t, int64(http.StatusNotFound), logged["status"])
+	require.Contains(t, logged, "request")
+
+	// When
+	// we write a server error
+	w = httptest.NewRecorder()
+	WriteError(w, r, logger, errors.New("database password incorrect"))
+
+	// Then
+	// the error message should not be included in the response
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	resp = ErrorResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, ErrorResponse{Error: "Internal Server Error"}, resp)
+}
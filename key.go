@@ -0,0 +1,114 @@
+package zerr
+
+import (
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// Key is a typed field key, which ensures that values attached to and read from errors
+// with the key have the same type. Keys are typically declared as package-level variables:
+//
+//	var UserID = zerr.NewKey[int64]("user_id")
+//
+//	err = UserID.With(zerr.Wrap(err), 42)
+//	...
+//	if id, ok := UserID.Get(err); ok { ... }
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a typed key with the field name 'name'
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the field name of the key
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Field creates a zap field with the value 'v', using zap.Any
+func (k Key[T]) Field(v T) zap.Field {
+	return zap.Any(k.name, v)
+}
+
+// With creates a new Error instance from 'e', with the value 'v' added.
+// This is equivalent to calling e.WithField(k.Field(v))
+func (k Key[T]) With(e *Error, v T) *Error {
+	return e.with(1, []zap.Field{k.Field(v)})
+}
+
+// Wrap adds the value 'v' to an error, in the same way as calling Wrap(err, k.Field(v))
+func (k Key[T]) Wrap(err error, v T) *Error {
+	return wrapWithStack(1, err, k.Field(v))
+}
+
+// Get returns the value of the field with the key, searching the error chain in the same way as Lookup.
+// False is returned if the field is missing, or if its value cannot be converted to T.
+// Numbers are only converted between types of the same class, e.g. from int64 to int8, and only
+// if the value fits in T. Integers and floating point numbers are never converted to each other
+func (k Key[T]) Get(err error) (T, bool) {
+	var zero T
+	f, ok := Lookup(err, k.name)
+	if !ok {
+		return zero, false
+	}
+
+	v := Value(f)
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+
+	// zap stores some values with a different type than they were created with,
+	// e.g. zap.Any stores an int as an int64, so we try converting them back.
+	// Only conversions within the same class of numbers are allowed, and only if the value fits in T
+	rv := reflect.ValueOf(v)
+	target := reflect.TypeOf(&zero).Elem()
+	if !rv.IsValid() || !rv.Type().ConvertibleTo(target) {
+		return zero, false
+	}
+	converted := reflect.New(target).Elem()
+	switch from, to := numberClassOf(rv.Kind()), numberClassOf(target.Kind()); {
+	case from != to:
+		return zero, false
+	case from == signedClass:
+		if converted.OverflowInt(rv.Int()) {
+			return zero, false
+		}
+	case from == unsignedClass:
+		if converted.OverflowUint(rv.Uint()) {
+			return zero, false
+		}
+	case from == floatClass:
+		if converted.OverflowFloat(rv.Float()) {
+			return zero, false
+		}
+	case rv.Kind() != target.Kind():
+		return zero, false
+	}
+	return rv.Convert(target).Interface().(T), true
+}
+
+// numberClass is the class of numbers that a kind belongs to
+type numberClass int
+
+const (
+	notNumber numberClass = iota
+	signedClass
+	unsignedClass
+	floatClass
+)
+
+// numberClassOf returns the class of numbers that 'k' belongs to
+func numberClassOf(k reflect.Kind) numberClass {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return signedClass
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedClass
+	case reflect.Float32, reflect.Float64:
+		return floatClass
+	}
+	return notNumber
+}
@@ -0,0 +1,95 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type tenantID string
+
+var (
+	testUserID  = NewKey[int]("user_id")
+	testTenant  = NewKey[tenantID]("tenant")
+	testTimeout = NewKey[time.Duration]("timeout")
+	testTags    = NewKey[[]string]("tags")
+)
+
+func TestKey(t *testing.T) {
+	// When
+	// values are attached with typed keys
+	e := testUserID.Wrap(errors.New("test"), 42)
+	e = testTenant.With(e, "acme")
+	e = testTimeout.With(e, 5*time.Second)
+	e = testTags.With(e, []string{"a", "b"})
+	err := fmt.Errorf("context: %w", e)
+
+	// Then
+	// they can be read back with the correct type
+	id, ok := testUserID.Get(err)
+	require.True(t, ok)
+	require.Equal(t, 42, id)
+
+	tenant, ok := testTenant.Get(err)
+	require.True(t, ok)
+	require.Equal(t, tenantID("acme"), tenant)
+
+	timeout, ok := testTimeout.Get(err)
+	require.True(t, ok)
+	require.Equal(t, 5*time.Second, timeout)
+
+	tags, ok := testTags.Get(err)
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, tags)
+
+	// And
+	// the fields can be read with the untyped accessors as well
+	i, ok := GetInt64(err, testUserID.Name())
+	require.True(t, ok)
+	require.Equal(t, int64(42), i)
+
+	// When
+	// a value is missing, or has another type
+	// Then
+	// false is returned
+	_, ok = NewKey[int]("missing").Get(err)
+	require.False(t, ok)
+	_, ok = NewKey[int]("tenant").Get(err)
+	require.False(t, ok)
+
+	// When
+	// a value is stored with a wider type by zap
+	e = WrapNoStack(errors.New("test"), zap.Int("small", 100), zap.Int("large", 1000), zap.Int("negative", -1),
+		zap.Uint64("unsigned", 7), zap.Float64("float", 1.7), zap.Float64("huge", 1e300))
+
+	// Then
+	// it is converted if it fits in the type of the key
+	small, ok := NewKey[int8]("small").Get(e)
+	require.True(t, ok)
+	require.Equal(t, int8(100), small)
+	unsigned, ok := NewKey[uint8]("unsigned").Get(e)
+	require.True(t, ok)
+	require.Equal(t, uint8(7), unsigned)
+	f, ok := NewKey[float32]("float").Get(e)
+	require.True(t, ok)
+	require.Equal(t, float32(1.7), f)
+
+	// And
+	// false is returned if it would overflow, or if the value is another kind of number
+	_, ok = NewKey[int8]("large").Get(e)
+	require.False(t, ok)
+	_, ok = NewKey[float32]("huge").Get(e)
+	require.False(t, ok)
+	_, ok = NewKey[uint]("negative").Get(e)
+	require.False(t, ok)
+	_, ok = NewKey[int]("unsigned").Get(e)
+	require.False(t, ok)
+	_, ok = NewKey[int]("float").Get(e)
+	require.False(t, ok)
+	_, ok = NewKey[float64]("small").Get(e)
+	require.False(t, ok)
+}
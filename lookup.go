@@ -0,0 +1,199 @@
+package zerr
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Lookup returns the field with the key 'key', searching the error chain from the outermost error.
+// If more than one field has the key, the field that is logged is returned, as selected by the FieldConflict
+// setting of the configuration. If all fields are kept, the outermost field is returned.
+// Fields added by zerr, such as "code", can be looked up as well.
+// With ConflictNamespace, the fields of each error are searched as if they were not namespaced.
+// Errors joined with Join are not searched, since they have separate fields
+func Lookup(err error, key string) (zap.Field, bool) {
+	if configOf(err).FieldConflict == ConflictNamespace {
+		var buf [16]layer
+		for _, l := range collectLayers(buf[:0], err) {
+			for _, f := range l.fields {
+				if f.Key == key && hasKey(f) {
+					return f, true
+				}
+			}
+		}
+	}
+
+	for _, f := range Fields(err) {
+		if f.Key == key && hasKey(f) {
+			return f, true
+		}
+	}
+	return zap.Field{}, false
+}
+
+// Value decodes the value stored in a zap field.
+// Integers, floats and other scalar types are returned with the same type they were created with,
+// e.g. a field created with zap.Int32 returns an int32, and zap.Int returns an int64.
+// Fields created with zap.Time return a time.Time, and fields storing an interface, such as
+// zap.Object or zap.Reflect, return the stored value.
+// Fields that do not contain a value, such as zap.Namespace and zap.Skip, return nil
+func Value(f zap.Field) interface{} {
+	switch f.Type {
+	case zapcore.BoolType:
+		return f.Integer == 1
+	case zapcore.DurationType:
+		return time.Duration(f.Integer)
+	case zapcore.Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case zapcore.Float32Type:
+		return math.Float32frombits(uint32(f.Integer))
+	case zapcore.Int64Type:
+		return f.Integer
+	case zapcore.Int32Type:
+		return int32(f.Integer)
+	case zapcore.Int16Type:
+		return int16(f.Integer)
+	case zapcore.Int8Type:
+		return int8(f.Integer)
+	case zapcore.Uint64Type:
+		return uint64(f.Integer)
+	case zapcore.Uint32Type:
+		return uint32(f.Integer)
+	case zapcore.Uint16Type:
+		return uint16(f.Integer)
+	case zapcore.Uint8Type:
+		return uint8(f.Integer)
+	case zapcore.UintptrType:
+		return uintptr(f.Integer)
+	case zapcore.StringType:
+		return f.String
+	case zapcore.TimeType:
+		if loc, ok := f.Interface.(*time.Location); ok {
+			return time.Unix(0, f.Integer).In(loc)
+		}
+		return time.Unix(0, f.Integer)
+	case zapcore.NamespaceType, zapcore.SkipType, zapcore.UnknownType:
+		return nil
+	default:
+		// The remaining types, e.g. TimeFullType, ErrorType and ObjectMarshalerType, store their value as an interface
+		return f.Interface
+	}
+}
+
+// GetAny returns the decoded value of the field with the key 'key'. See Lookup and Value for details
+func GetAny(err error, key string) (interface{}, bool) {
+	f, ok := Lookup(err, key)
+	if !ok {
+		return nil, false
+	}
+	return Value(f), true
+}
+
+// GetString returns the value of the string field with the key 'key'.
+// Fields created with zap.String, zap.Stringer and zap.ByteString are supported
+func GetString(err error, key string) (string, bool) {
+	f, ok := Lookup(err, key)
+	if !ok {
+		return "", false
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String, true
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return s.String(), true
+		}
+	case zapcore.ByteStringType:
+		if b, ok := f.Interface.([]byte); ok {
+			return string(b), true
+		}
+	}
+	return "", false
+}
+
+// GetInt64 returns the value of the signed integer field with the key 'key'.
+// Fields created with zap.Int, zap.Int64, zap.Int32, zap.Int16 and zap.Int8 are supported
+func GetInt64(err error, key string) (int64, bool) {
+	f, ok := Lookup(err, key)
+	if !ok {
+		return 0, false
+	}
+	switch f.Type {
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return f.Integer, true
+	}
+	return 0, false
+}
+
+// GetUint64 returns the value of the unsigned integer field with the key 'key'.
+// Fields created with zap.Uint, zap.Uint64, zap.Uint32, zap.Uint16, zap.Uint8 and zap.Uintptr are supported
+func GetUint64(err error, key string) (uint64, bool) {
+	f, ok := Lookup(err, key)
+	if !ok {
+		return 0, false
+	}
+	switch f.Type {
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return uint64(f.Integer), true
+	}
+	return 0, false
+}
+
+// GetFloat64 returns the value of the float field with the key 'key'.
+// Fields created with zap.Float64 and zap.Float32 are supported
+func GetFloat64(err error, key string) (float64, bool) {
+	f, ok := Lookup(err, key)
+	if !ok {
+		return 0, false
+	}
+	switch f.Type {
+	case zapcore.Float64Type:
+		return math.Float64frombits(uint64(f.Integer)), true
+	case zapcore.Float32Type:
+		return float64(math.Float32frombits(uint32(f.Integer))), true
+	}
+	return 0, false
+}
+
+// GetBool returns the value of the bool field with the key 'key'
+func GetBool(err error, key string) (bool, bool) {
+	f, ok := Lookup(err, key)
+	if !ok || f.Type != zapcore.BoolType {
+		return false, false
+	}
+	return f.Integer == 1, true
+}
+
+// GetDuration returns the value of the duration field with the key 'key'
+func GetDuration(err error, key string) (time.Duration, bool) {
+	f, ok := Lookup(err, key)
+	if !ok || f.Type != zapcore.DurationType {
+		return 0, false
+	}
+	return time.Duration(f.Integer), true
+}
+
+// GetTime returns the value of the time field with the key 'key'
+func GetTime(err error, key string) (time.Time, bool) {
+	f, ok := Lookup(err, key)
+	if !ok || (f.Type != zapcore.TimeType && f.Type != zapcore.TimeFullType) {
+		return time.Time{}, false
+	}
+	t, ok := Value(f).(time.Time)
+	return t, ok
+}
+
+// GetError returns the value of the error field with the key 'key'.
+// Fields created with zap.Error and zap.NamedError are supported
+func GetError(err error, key string) (error, bool) {
+	f, ok := Lookup(err, key)
+	if !ok || f.Type != zapcore.ErrorType {
+		return nil, false
+	}
+	e, ok := f.Interface.(error)
+	return e, ok
+}
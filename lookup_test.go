@@ -0,0 +1,111 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLookup(t *testing.T) {
+	now := time.Now()
+	cause := errors.New("cause")
+	var err error = WrapNoStack(errors.New("test"),
+		zap.String("tenant_id", "inner"),
+		zap.Int32("int", -5),
+		zap.Uint16("uint", 7),
+		zap.Float32("float", 1.5),
+		zap.Bool("bool", true),
+		zap.Duration("duration", time.Second),
+		zap.Time("time", now),
+		zap.Stringer("ip", net.IPv4(127, 0, 0, 1)),
+		zap.NamedError("cause", cause),
+	)
+	err = fmt.Errorf("context: %w", Wrap(err, zap.String("tenant_id", "outer")))
+
+	// When
+	// a key is present in multiple layers
+	// Then
+	// the outermost field is returned
+	f, ok := Lookup(err, "tenant_id")
+	require.True(t, ok)
+	require.Equal(t, "outer", f.String)
+
+	// When
+	// the configuration specifies that the innermost field wins
+	config := NewConfig()
+	config.FieldConflict = ConflictInnermostWins
+	inner := config.Wrap(config.Wrap(errors.New("test"), zap.String("tenant_id", "inner")), zap.String("tenant_id", "outer"))
+
+	// Then
+	// the field that is logged is returned
+	f, ok = Lookup(inner, "tenant_id")
+	require.True(t, ok)
+	require.Equal(t, "inner", f.String)
+
+	// When
+	// a key is missing
+	// Then
+	// false is returned
+	_, ok = Lookup(err, "missing")
+	require.False(t, ok)
+	_, ok = GetString(err, "missing")
+	require.False(t, ok)
+
+	// When
+	// typed values are read
+	// Then
+	// they are decoded
+	s, ok := GetString(err, "tenant_id")
+	require.True(t, ok)
+	require.Equal(t, "outer", s)
+
+	s, ok = GetString(err, "ip")
+	require.True(t, ok)
+	require.Equal(t, "127.0.0.1", s)
+
+	i, ok := GetInt64(err, "int")
+	require.True(t, ok)
+	require.Equal(t, int64(-5), i)
+
+	u, ok := GetUint64(err, "uint")
+	require.True(t, ok)
+	require.Equal(t, uint64(7), u)
+
+	fl, ok := GetFloat64(err, "float")
+	require.True(t, ok)
+	require.Equal(t, 1.5, fl)
+
+	b, ok := GetBool(err, "bool")
+	require.True(t, ok)
+	require.True(t, b)
+
+	d, ok := GetDuration(err, "duration")
+	require.True(t, ok)
+	require.Equal(t, time.Second, d)
+
+	tm, ok := GetTime(err, "time")
+	require.True(t, ok)
+	require.True(t, now.Equal(tm))
+
+	e, ok := GetError(err, "cause")
+	require.True(t, ok)
+	require.Equal(t, cause, e)
+
+	v, ok := GetAny(err, "int")
+	require.True(t, ok)
+	require.Equal(t, int32(-5), v)
+
+	// When
+	// a value is read with the wrong type
+	// Then
+	// false is returned
+	_, ok = GetInt64(err, "tenant_id")
+	require.False(t, ok)
+	_, ok = GetDuration(err, "int")
+	require.False(t, ok)
+}
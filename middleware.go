@@ -0,0 +1,79 @@
+package zerr
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// CaptureBody is a middleware that makes the body of incoming requests available when logging them
+// with FieldRequest or Error.WithRequest.
+// Server requests do not have GetBody set, so their bodies are normally not logged.
+// CaptureBody copies the data read by the handler into a buffer, bounded by the MaxSize of 'policy', and installs
+// a GetBody function returning the data read so far. The body is never read ahead of the handler, so
+// streaming bodies and "Expect: 100-continue" requests work as before.
+// If 'policy' is nil, DefaultBodyPolicy is used. The same policy should be used when logging the request,
+// e.g. with FieldRequestWithPolicy, so that truncated bodies are marked as such
+//
+//	http.Handle("/", zerr.CaptureBody(handler, nil))
+func CaptureBody(next http.Handler, policy *BodyPolicy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxSize := bodyPolicyOrDefault(policy).MaxSize
+		if r.Body == nil || r.Body == http.NoBody || r.GetBody != nil || maxSize <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body := &capturedBody{
+			body:    r.Body,
+			maxSize: maxSize,
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.Body = body
+		r2.GetBody = body.captured
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// capturedBody copies the data read from the original body into a bounded buffer
+type capturedBody struct {
+	body    io.ReadCloser
+	maxSize int
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Read reads from the original body, and copies the data into the buffer until it is full
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.mu.Lock()
+		// One extra byte is kept, so that truncation can be detected when the body is logged
+		if room := b.maxSize + 1 - len(b.buf); room > 0 {
+			if room > n {
+				room = n
+			}
+			b.buf = append(b.buf, p[:room]...)
+		}
+		b.mu.Unlock()
+	}
+	return n, err
+}
+
+// Close closes the original body
+func (b *capturedBody) Close() error {
+	return b.body.Close()
+}
+
+// captured returns a reader for the data that has been read so far.
+// The buffer is only appended to, so the returned data does not change when more is read
+func (b *capturedBody) captured() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return ioutil.NopCloser(bytes.NewReader(b.buf[:len(b.buf):len(b.buf)])), nil
+}
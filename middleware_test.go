@@ -0,0 +1,76 @@
+package zerr
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureBody(t *testing.T) {
+	body := strings.Repeat("x", DefaultBodyPolicy.MaxSize+100)
+
+	var handlerBody []byte
+	var before, after map[string]interface{}
+	handler := CaptureBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// When
+		// the request is logged before and after the handler reads the body
+		before = encodeObject(t, &Request{Request: r})
+
+		var err error
+		handlerBody, err = ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		after = encodeObject(t, &Request{Request: r})
+	}), nil)
+
+	// When
+	// a request is sent through the middleware
+	r := httptest.NewRequest("POST", "http://www.example.com", strings.NewReader(body))
+	r.Header.Set("Content-Type", "text/plain")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Then
+	// the handler should receive the complete body
+	require.Equal(t, body, string(handlerBody))
+
+	// And
+	// nothing should be read before the handler reads the body
+	require.Empty(t, before["Body"])
+
+	// And
+	// the body logged after reading should be truncated
+	require.True(t, strings.HasPrefix(after["Body"].(string), strings.Repeat("x", DefaultBodyPolicy.MaxSize)+"... [truncated"))
+}
+
+func TestCaptureBodyStreaming(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	policy := &BodyPolicy{MaxSize: 3}
+	var logged map[string]interface{}
+	handler := CaptureBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// When
+		// the handler reads part of a body that is still being written
+		go func() {
+			_, _ = pw.Write([]byte("first"))
+		}()
+		buf := make([]byte, 5)
+		_, err := io.ReadFull(r.Body, buf)
+		require.NoError(t, err)
+
+		logged = encodeObject(t, policyRequest{Request: r, bodyPolicy: policy})
+	}), policy)
+
+	// Then
+	// the middleware should not block, and the data read so far should be logged,
+	// bounded by the size of the policy
+	r := httptest.NewRequest("POST", "http://www.example.com", pr)
+	r.Header.Set("Content-Type", "text/plain")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	require.True(t, strings.HasPrefix(logged["Body"].(string), "fir... [truncated"))
+}
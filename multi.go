@@ -0,0 +1,103 @@
+package zerr
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// multiError holds multiple errors, each with their own set of fields
+type multiError struct {
+	errs []error
+}
+
+// Error returns the messages of all errors, separated by newlines
+func (m *multiError) Error() string {
+	msgs := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the list of errors, which allows errors.Is and errors.As to inspect them
+func (m *multiError) Unwrap() []error {
+	return m.errs
+}
+
+// Is reports whether any of the errors matches 'target'.
+// errors.Is only follows 'Unwrap() []error' from Go 1.20, so this is needed for older versions
+func (m *multiError) Is(target error) bool {
+	for _, err := range m.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches 'target'. See Is for details
+func (m *multiError) As(target interface{}) bool {
+	for _, err := range m.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// field returns a zap field containing all errors, each with their own fields
+func (m *multiError) field() zap.Field {
+	return zap.Array("errors", errorArray(m.errs))
+}
+
+// errorArray is an array of errors that implements zapcore.ArrayMarshaler
+type errorArray []error
+
+// MarshalLogArray encodes each error as an object with its message and fields
+func (a errorArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range a {
+		if err := enc.AppendObject(errorObject{err}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorObject is a wrapper around an error that implements zapcore.ObjectMarshaler
+type errorObject struct {
+	err error
+}
+
+// MarshalLogObject encodes the error message and all fields attached to the error
+func (o errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", o.err.Error())
+	for _, f := range Fields(o.err) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// Join returns an error that wraps the given errors. Any nil errors are discarded,
+// and nil is returned if all errors are nil.
+// Each error keeps its own fields and stacktrace, and when the returned error is
+// logged, they are added as an array of objects with the key "errors".
+// Like errors.Join, the result is an error and not an *Error, so that a nil result
+// can be returned directly without creating a non-nil interface
+func Join(errs ...error) error {
+	nonNil := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 0 {
+		return nil
+	}
+
+	return &Error{
+		err: &multiError{errs: nonNil},
+	}
+}
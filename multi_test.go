@@ -0,0 +1,58 @@
+package zerr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestJoin(t *testing.T) {
+	err1 := WrapNoStack(errors.New("first"), zap.Int("intfield", 1))
+	err2 := Wrap(errors.New("second"), zap.String("stringfield", "abc"))
+
+	// When
+	// only nil errors are joined
+	// Then
+	// nil is returned, also when stored in an error interface
+	var err error = Join(nil, nil)
+	require.True(t, err == nil)
+
+	// When
+	// we join multiple errors
+	e := Join(err1, nil, err2)
+
+	// Then
+	// the joined errors should still be reachable
+	require.True(t, errors.Is(e, err1))
+	require.True(t, errors.Is(e, err2))
+	var code Code
+	require.True(t, errors.As(Join(err1, CodeNotFound), &code))
+	require.Equal(t, CodeNotFound, code)
+	require.Equal(t, "first\nsecond", e.Error())
+
+	// And
+	// a single field with all errors should be available
+	fields := WrapNoStack(e, zap.Int("outer", 2)).Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "errors", fields[1].Key)
+
+	// And
+	// each error should be encoded with its own fields
+	enc := zapcore.NewMapObjectEncoder()
+	fields[1].AddTo(enc)
+	encoded := enc.Fields["errors"].([]interface{})
+	require.Len(t, encoded, 2)
+
+	first := encoded[0].(map[string]interface{})
+	require.Equal(t, "first", first["error"])
+	require.Equal(t, int64(1), first["intfield"])
+
+	second := encoded[1].(map[string]interface{})
+	require.Equal(t, "second", second["error"])
+	require.Equal(t, "abc", second["stringfield"])
+	require.Contains(t, second, "stacktrace")
+	require.NotContains(t, second, "intfield")
+}
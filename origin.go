@@ -0,0 +1,85 @@
+package zerr
+
+import (
+	"context"
+	"errors"
+)
+
+// Origin holds the stacktrace of the location where a goroutine was started.
+// The stacktrace of an error created in a goroutine ends where the goroutine starts,
+// so attaching the origin to the error shows who started the work as well
+type Origin struct {
+	stack *stack
+}
+
+// CaptureOrigin captures the stacktrace of the caller, to be attached to errors
+// returned by goroutines started by the caller
+//
+//	origin := zerr.CaptureOrigin()
+//	go func() {
+//	    if err := work(); err != nil {
+//	        errs <- zerr.Wrap(err).WithOrigin(origin)
+//	    }
+//	}()
+func CaptureOrigin() *Origin {
+	return &Origin{stack: callers(1, DefaultConfig())}
+}
+
+// WithOrigin creates a new Error instance, with the origin stacktrace attached.
+// The stacktrace is logged with the key "origin_stacktrace", in addition to the stacktrace of the error itself.
+// If 'o' is nil, 'e' is returned
+func (e *Error) WithOrigin(o *Origin) *Error {
+	if o == nil {
+		return e
+	}
+	newErr := e.with(1, nil)
+	newErr.origin = o.stack
+	return newErr
+}
+
+type originKey struct{}
+
+// ContextWithOrigin returns a copy of 'ctx' carrying the stacktrace of the caller,
+// which can be retrieved with OriginFromContext in goroutines that receive the context
+func ContextWithOrigin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, originKey{}, &Origin{stack: callers(1, DefaultConfig())})
+}
+
+// OriginFromContext returns the origin stored in 'ctx' by ContextWithOrigin, or nil if none is available
+func OriginFromContext(ctx context.Context) *Origin {
+	o, _ := ctx.Value(originKey{}).(*Origin)
+	return o
+}
+
+// Go runs 'fn' in a new goroutine, and returns a channel that receives the error returned by 'fn'.
+// If 'fn' returns an error, the stacktrace of the caller of Go is attached to it as the origin.
+// The channel is closed when 'fn' returns, so receiving from it yields nil if 'fn' succeeded
+//
+//	errc := zerr.Go(func() error { return work() })
+//	...
+//	if err := <-errc; err != nil { ... }
+func Go(fn func() error) <-chan error {
+	origin := &Origin{stack: callers(1, DefaultConfig())}
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		if err := fn(); err != nil {
+			ch <- attachOrigin(err, origin)
+		}
+	}()
+	return ch
+}
+
+// attachOrigin wraps 'err' with the origin 'o'
+func attachOrigin(err error, o *Origin) *Error {
+	var e *Error
+	hasStack := false
+	if errors.As(err, &e) {
+		hasStack = e.hasStack
+	}
+	return &Error{
+		err:      err,
+		origin:   o.stack,
+		hasStack: hasStack,
+	}
+}
@@ -0,0 +1,61 @@
+package zerr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGo(t *testing.T) {
+	// When
+	// a goroutine started with Go succeeds
+	// Then
+	// nil is received
+	require.NoError(t, <-Go(func() error { return nil }))
+
+	// When
+	// a goroutine started with Go returns an error
+	err := <-Go(func() error { return Wrap(errors.New("test")) })
+
+	// Then
+	// the error should have both its own stacktrace and the origin stacktrace
+	fields := Fields(err)
+	require.Len(t, fields, 2)
+	require.Equal(t, "origin_stacktrace", fields[0].Key)
+	require.Equal(t, "stacktrace", fields[1].Key)
+
+	// And
+	// the origin should point to the caller of Go
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	frames := e.origin.frames()
+	require.Equal(t, "github.com/yzzyx/zerr.TestGo", frames[0].Function)
+}
+
+func TestContextOrigin(t *testing.T) {
+	// When
+	// the context has no origin
+	// Then
+	// nil is returned, and WithOrigin does nothing
+	e := WrapNoStack(errors.New("test"))
+	require.Nil(t, OriginFromContext(context.Background()))
+	require.Equal(t, e, e.WithOrigin(OriginFromContext(context.Background())))
+
+	// When
+	// an origin is stored in the context, and attached to an error in a goroutine
+	ctx := ContextWithOrigin(context.Background())
+	ch := make(chan *Error)
+	go func() {
+		ch <- Wrap(errors.New("test")).WithOrigin(OriginFromContext(ctx))
+	}()
+	e = <-ch
+
+	// Then
+	// the origin stacktrace should be included
+	fields := e.Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "origin_stacktrace", fields[0].Key)
+	require.Equal(t, "github.com/yzzyx/zerr.TestContextOrigin", e.origin.frames()[0].Function)
+}
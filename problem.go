@@ -0,0 +1,234 @@
+package zerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents
+const ProblemContentType = "application/problem+json"
+
+// problemMembers lists the members defined by RFC 7807, which cannot be used as extensions
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// Problem is a RFC 7807 problem details document
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions contains any additional members of the document
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON encodes the problem, with the extensions as top-level members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		if !problemMembers[k] {
+			doc[k] = v
+		}
+	}
+
+	doc["type"] = p.Type
+	if p.Type == "" {
+		doc["type"] = "about:blank"
+	}
+	if p.Title != "" {
+		doc["title"] = p.Title
+	}
+	if p.Status != 0 {
+		doc["status"] = p.Status
+	}
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a problem document. Any members not defined by RFC 7807 are added to Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	members := map[string]interface{}{
+		"type":     &p.Type,
+		"title":    &p.Title,
+		"status":   &p.Status,
+		"detail":   &p.Detail,
+		"instance": &p.Instance,
+	}
+	for k, raw := range doc {
+		if dst, ok := members[k]; ok {
+			// RFC 7807 specifies that members with the wrong type should be ignored
+			_ = json.Unmarshal(raw, dst)
+			continue
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions[k] = v
+	}
+	return nil
+}
+
+// Err converts the problem to an Error. The error message is taken from the detail member,
+// or from the title if no detail is available.
+// The members type, title, status and instance are added as the fields "problem_type", "title",
+// "status" and "instance", if they are set. Extension members are added as fields, except for "code"
+// which is used as the error code. If there is no "code" member, the code is selected from the status.
+// Client errors (4xx) without a corresponding code get CodeInvalidArgument, so that they are not
+// treated as internal errors by StatusCode
+func (p *Problem) Err() *Error {
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	if msg == "" {
+		msg = http.StatusText(p.Status)
+	}
+
+	keys := make([]string, 0, len(p.Extensions))
+	for k := range p.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, 0, len(keys)+4)
+	if p.Type != "" && p.Type != "about:blank" {
+		fields = append(fields, zap.String("problem_type", p.Type))
+	}
+	if p.Title != "" {
+		fields = append(fields, zap.String("title", p.Title))
+	}
+	if p.Status != 0 {
+		fields = append(fields, zap.Int("status", p.Status))
+	}
+	if p.Instance != "" {
+		fields = append(fields, zap.String("instance", p.Instance))
+	}
+
+	code := codeForStatus(p.Status)
+	if code == CodeUnknown && p.Status >= 400 && p.Status < 500 {
+		code = CodeInvalidArgument
+	}
+	for _, k := range keys {
+		if c, ok := p.Extensions[k].(string); ok && k == "code" {
+			code = Code(c)
+			continue
+		}
+		fields = append(fields, zap.Any(k, p.Extensions[k]))
+	}
+
+	e := WrapNoStack(errors.New(msg), fields...)
+	if code != CodeUnknown {
+		e = e.WithCode(code)
+	}
+	return e
+}
+
+// NewProblem creates a problem document from an error.
+// The status is selected with StatusCode, and the title member is set to the standard status text.
+// For client errors (4xx), the detail member is set to the error message. For all other status codes
+// it is left empty, in order to not leak internal information. A nil error results in a problem with status 500.
+// Fields attached to the error are only included as extension members if their key is listed in 'keys'.
+// If the error has a code, it is always included as the extension member "code"
+func NewProblem(err error, keys ...string) *Problem {
+	status := StatusCode(err)
+	p := &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if err != nil && status >= 400 && status < 500 {
+		p.Detail = err.Error()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range Fields(err) {
+		f.AddTo(enc)
+	}
+	for _, k := range keys {
+		if v, ok := enc.Fields[k]; ok && !problemMembers[k] {
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{})
+			}
+			p.Extensions[k] = v
+		}
+	}
+
+	if c := GetCode(err); c != CodeUnknown {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["code"] = string(c)
+	}
+	return p
+}
+
+// WriteProblem writes 'err' as a problem document to 'w', with the instance member set to the path of 'r'.
+// See NewProblem for a description of how 'keys' are used
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, keys ...string) {
+	p := NewProblem(err, keys...)
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// DecodeProblem reads a problem document from 'r'
+func DecodeProblem(r io.Reader) (*Problem, error) {
+	p := &Problem{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseProblem reads a problem document from the body of 'resp', and converts it to an Error.
+// An error is returned if the response does not contain a problem document
+func ParseProblem(resp *http.Response) (*Error, error) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != ProblemContentType {
+		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	p, err := DecodeProblem(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+	return p.Err(), nil
+}
@@ -0,0 +1,114 @@
+package zerr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProblem(t *testing.T) {
+	err := WrapCode(errors.New("item not found"), CodeNotFound,
+		zap.String("item", "abc"), zap.Int("attempt", 2), zap.String("secret", "internal"))
+
+	// When
+	// we create a problem from an error
+	p := NewProblem(err, "item", "attempt", "missing")
+
+	// Then
+	// the standard members should be set
+	require.Equal(t, http.StatusNotFound, p.Status)
+	require.Equal(t, "Not Found", p.Title)
+	require.Equal(t, "item not found", p.Detail)
+
+	// And
+	// only the selected fields and the code should be included as extensions
+	require.Equal(t, map[string]interface{}{
+		"item":    "abc",
+		"attempt": int64(2),
+		"code":    "not_found",
+	}, p.Extensions)
+
+	// When
+	// we write the problem as a response
+	w := httptest.NewRecorder()
+	WriteProblem(w, httptest.NewRequest("GET", "http://www.example.com/item/abc", nil), err, "item", "attempt")
+
+	// Then
+	// it should be a problem document
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "item not found",
+		"instance": "/item/abc",
+		"item": "abc",
+		"attempt": 2,
+		"code": "not_found"
+	}`, w.Body.String())
+
+	// When
+	// we parse the response
+	e, parseErr := ParseProblem(w.Result())
+	require.NoError(t, parseErr)
+
+	// Then
+	// the message, code and extensions should be restored
+	require.Equal(t, "item not found", e.Error())
+	require.True(t, errors.Is(e, CodeNotFound))
+
+	fields := e.Fields()
+	require.Len(t, fields, 6)
+	require.Equal(t, "title", fields[0].Key)
+	require.Equal(t, "status", fields[1].Key)
+	require.Equal(t, "instance", fields[2].Key)
+	require.Equal(t, "attempt", fields[3].Key)
+	require.Equal(t, "item", fields[4].Key)
+	require.Equal(t, "code", fields[5].Key)
+
+	// When
+	// we parse a problem without a code
+	w = httptest.NewRecorder()
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_, _ = w.WriteString(`{"type": "https://example.com/invalid", "title": "Invalid input", "status": 422}`)
+	e, parseErr = ParseProblem(w.Result())
+	require.NoError(t, parseErr)
+
+	// Then
+	// the code should be selected from the status, and the members should be available as fields
+	require.Equal(t, http.StatusBadRequest, StatusCode(e))
+	require.Equal(t, CodeInvalidArgument, GetCode(e))
+	require.Equal(t, "Invalid input", e.Error())
+	typ, ok := Lookup(e, "problem_type")
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/invalid", typ.String)
+
+	// When
+	// we create a problem from a server error
+	p = NewProblem(errors.New("db password=hunter2 rejected"))
+
+	// Then
+	// the error message should not be included
+	require.Equal(t, http.StatusInternalServerError, p.Status)
+	require.Equal(t, "Internal Server Error", p.Title)
+	require.Empty(t, p.Detail)
+
+	// When
+	// we create a problem from a nil error
+	// Then
+	// it should not panic
+	require.Equal(t, http.StatusInternalServerError, NewProblem(nil).Status)
+
+	// When
+	// we parse a response that isn't a problem document
+	// Then
+	// an error is returned
+	_, parseErr = ParseProblem(httptest.NewRecorder().Result())
+	require.Error(t, parseErr)
+}
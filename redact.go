@@ -0,0 +1,152 @@
+package zerr
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactionPolicy specifies which header and query parameter values should be masked when logged
+type RedactionPolicy struct {
+	// Headers is a list of header names which should be masked. The names are case-insensitive
+	Headers []string
+
+	// QueryParams is a list of patterns matching the names of query parameters which should be masked
+	QueryParams []*regexp.Regexp
+
+	// Mask is the value used to replace masked values
+	Mask string
+}
+
+// DefaultRedactionPolicy is the policy used when logging headers and query parameters,
+// unless another policy has been specified.
+// It should only be modified during initialization
+var DefaultRedactionPolicy = &RedactionPolicy{
+	Headers: []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+		"Api-Key",
+		"X-Auth-Token",
+		"X-Csrf-Token",
+		"X-Xsrf-Token",
+		"X-Amz-Security-Token",
+	},
+	QueryParams: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[-_]?key|signature)`),
+		regexp.MustCompile(`(?i)^(key|code|sig|auth)$`),
+	},
+	Mask: "[REDACTED]",
+}
+
+// policyOrDefault returns 'p', or DefaultRedactionPolicy if 'p' is nil
+func policyOrDefault(p *RedactionPolicy) *RedactionPolicy {
+	if p == nil {
+		return DefaultRedactionPolicy
+	}
+	return p
+}
+
+// RedactHeader reports whether the value of the header 'name' should be masked
+func (p *RedactionPolicy) RedactHeader(name string) bool {
+	if p == nil {
+		return false
+	}
+	name = http.CanonicalHeaderKey(name)
+	for _, h := range p.Headers {
+		if http.CanonicalHeaderKey(h) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactQueryParam reports whether the value of the query parameter 'name' should be masked
+func (p *RedactionPolicy) RedactQueryParam(name string) bool {
+	if p == nil {
+		return false
+	}
+	for _, re := range p.QueryParams {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask returns a list of the same length as 'values', where each value has been replaced by the mask
+func (p *RedactionPolicy) mask(values []string) StringArray {
+	masked := make(StringArray, len(values))
+	for i := range masked {
+		masked[i] = p.Mask
+	}
+	return masked
+}
+
+// URL returns the string representation of 'u', with any password and sensitive query parameters masked
+func (p *RedactionPolicy) URL(u *url.URL) string {
+	if p == nil || u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	query := u.Query()
+	redacted := false
+	for k, v := range query {
+		if p.RedactQueryParam(k) {
+			query[k] = p.mask(v)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.Redacted()
+	}
+
+	c := *u
+	c.RawQuery = query.Encode()
+	return c.Redacted()
+}
+
+// marshalHeader encodes headers with a zapcore.ObjectEncoder, masking values according to 'p'
+func marshalHeader(enc zapcore.ObjectEncoder, h http.Header, p *RedactionPolicy) (err error) {
+	for k, v := range h {
+		if p.RedactHeader(k) {
+			err = enc.AddArray(k, p.mask(v))
+		} else {
+			err = enc.AddArray(k, StringArray(v))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalURLValues encodes url values with a zapcore.ObjectEncoder, masking values according to 'p'
+func marshalURLValues(enc zapcore.ObjectEncoder, values url.Values, p *RedactionPolicy) (err error) {
+	for k, v := range values {
+		if p.RedactQueryParam(k) {
+			err = enc.AddArray(k, p.mask(v))
+		} else {
+			err = enc.AddArray(k, StringArray(v))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactedHeader is a wrapper around http.Header that implements zapcore.ObjectMarshaler with a specific policy
+type redactedHeader struct {
+	header http.Header
+	policy *RedactionPolicy
+}
+
+// MarshalLogObject encodes headers with a zapcore.ObjectEncoder
+func (h redactedHeader) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return marshalHeader(enc, h.header, h.policy)
+}
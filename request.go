@@ -3,7 +3,6 @@ package zerr
 import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 )
@@ -22,45 +21,56 @@ func (a StringArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
 // URLValues is a wrapper around url.Values that implements zapcore.ObjectMarshaler
 type URLValues url.Values
 
-// MarshalLogObject encodes url values with a zapcore.ObjectEncoder
-func (values URLValues) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
-	for k, v := range values {
-		err = enc.AddArray(k, StringArray(v))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+// MarshalLogObject encodes url values with a zapcore.ObjectEncoder.
+// Values are masked according to DefaultRedactionPolicy
+func (values URLValues) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return marshalURLValues(enc, url.Values(values), DefaultRedactionPolicy)
 }
 
 // Header is a wrapper around http.Header that implements zapcore.ObjectMarshaler
 type Header http.Header
 
-// MarshalLogObject encodes headers with a zapcore.ObjectEncoder
-func (h Header) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
-	for k, v := range h {
-		err = enc.AddArray(k, StringArray(v))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+// MarshalLogObject encodes headers with a zapcore.ObjectEncoder.
+// Values are masked according to DefaultRedactionPolicy
+func (h Header) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return marshalHeader(enc, http.Header(h), DefaultRedactionPolicy)
 }
 
-// Request is a wrapper around http.Request that implements zapcore.ObjectMarshaler
+// Request is a wrapper around http.Request that implements zapcore.ObjectMarshaler.
+// Headers and query parameters are masked according to DefaultRedactionPolicy, and the body is
+// logged according to DefaultBodyPolicy. Use FieldRequestWithPolicy to log a request with other policies
 type Request struct {
 	*http.Request
 }
 
 // MarshalLogObject encodes request with a zapcore.ObjectEncoder
 func (r *Request) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return policyRequest{Request: r.Request}.MarshalLogObject(enc)
+}
+
+// policyRequest is a wrapper around http.Request that implements zapcore.ObjectMarshaler with specific policies
+type policyRequest struct {
+	*http.Request
+
+	// redaction specifies which headers and query parameters are masked.
+	// If nil, DefaultRedactionPolicy is used
+	redaction *RedactionPolicy
+
+	// bodyPolicy specifies how the body is logged.
+	// If nil, DefaultBodyPolicy is used
+	bodyPolicy *BodyPolicy
+}
+
+// MarshalLogObject encodes request with a zapcore.ObjectEncoder
+func (r policyRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	policy := policyOrDefault(r.redaction)
 
 	enc.AddString("Method", r.Method)
 	if r.URL != nil {
-		enc.AddString("Url", r.URL.String())
+		enc.AddString("Url", policy.URL(r.URL))
 	}
 
-	err := enc.AddObject("Header", Header(r.Header))
+	err := enc.AddObject("Header", redactedHeader{r.Header, policy})
 	if err != nil {
 		return err
 	}
@@ -75,11 +85,15 @@ func (r *Request) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddString("Host", r.Host)
 	enc.AddString("RemoteAddr", r.RemoteAddr)
 
-	if r.GetBody != nil {
+	bodyPolicy := bodyPolicyOrDefault(r.bodyPolicy)
+	if r.GetBody != nil && bodyPolicy.MaxSize > 0 {
 		if bodyCopy, err := r.GetBody(); err == nil && bodyCopy != nil {
-			body, err := ioutil.ReadAll(bodyCopy)
+			data, err := bodyPolicy.readBody(bodyCopy)
+			bodyCopy.Close()
 			if err == nil {
-				enc.AddByteString("Body", body)
+				body, truncated := bodyPolicy.preview(data)
+				return marshalBody(enc, "Body", r.Header.Get("Content-Type"), body, truncated, r.ContentLength,
+					bodyPolicy, policy)
 			}
 		}
 	}
@@ -89,5 +103,11 @@ func (r *Request) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 
 // FieldRequest converts a http request to a zap request, which is compatible with the zap ObjectMarshaler interface
 func FieldRequest(key string, r *http.Request) zap.Field {
-	return zap.Object(key, &Request{r})
+	return zap.Object(key, &Request{Request: r})
+}
+
+// FieldRequestWithPolicy is like FieldRequest, but masks headers and query parameters according to 'redaction',
+// and logs the body according to 'body'. If a policy is nil, the default policy is used
+func FieldRequestWithPolicy(key string, r *http.Request, redaction *RedactionPolicy, body *BodyPolicy) zap.Field {
+	return zap.Object(key, policyRequest{Request: r, redaction: redaction, bodyPolicy: body})
 }
@@ -0,0 +1,120 @@
+package zerr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Response is a wrapper around http.Response that implements zapcore.ObjectMarshaler.
+// Use NewResponse to create a Response with a preview of the body
+type Response struct {
+	*http.Response
+
+	// Redaction specifies which headers and query parameters are masked.
+	// If nil, DefaultRedactionPolicy is used
+	Redaction *RedactionPolicy
+
+	bodyPolicy *BodyPolicy
+	body       []byte
+	truncated  bool
+	hasBody    bool
+}
+
+// NewResponse creates a Response, and reads a preview of the body according to DefaultBodyPolicy.
+// The body of 'resp' is replaced, so that the complete body can still be read by the caller
+func NewResponse(resp *http.Response) *Response {
+	r := &Response{
+		Response:   resp,
+		bodyPolicy: DefaultBodyPolicy,
+	}
+	if resp == nil || resp.Body == nil || resp.Body == http.NoBody || r.bodyPolicy.MaxSize <= 0 {
+		return r
+	}
+
+	// All data that was read is replayed, also if reading failed. In that case, the error is
+	// returned to the caller after the data, instead of continuing with the original body
+	data, err := r.bodyPolicy.readBody(resp.Body)
+	var rest io.Reader = resp.Body
+	if err != nil {
+		rest = errorReader{err}
+	} else {
+		r.body, r.truncated = r.bodyPolicy.preview(data)
+		r.hasBody = true
+	}
+	resp.Body = &replayedBody{
+		Reader: io.MultiReader(bytes.NewReader(data), rest),
+		Closer: resp.Body,
+	}
+	return r
+}
+
+// MarshalLogObject encodes response with a zapcore.ObjectEncoder
+func (r *Response) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if r.Response == nil {
+		return nil
+	}
+	policy := policyOrDefault(r.Redaction)
+
+	enc.AddString("Status", r.Status)
+	enc.AddInt("StatusCode", r.StatusCode)
+
+	err := enc.AddObject("Header", redactedHeader{r.Header, policy})
+	if err != nil {
+		return err
+	}
+	enc.AddInt64("ContentLength", r.ContentLength)
+
+	if r.Request != nil {
+		err = enc.AddObject("Request", requestSummary{r.Request, policy})
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.hasBody {
+		return marshalBody(enc, "Body", r.Header.Get("Content-Type"), r.body, r.truncated, r.ContentLength,
+			r.bodyPolicy, policy)
+	}
+	return nil
+}
+
+// requestSummary encodes the method and url of a request
+type requestSummary struct {
+	*http.Request
+	policy *RedactionPolicy
+}
+
+// MarshalLogObject encodes the request summary with a zapcore.ObjectEncoder
+func (r requestSummary) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("Method", r.Method)
+	if r.URL != nil {
+		enc.AddString("Url", r.policy.URL(r.URL))
+	}
+	return nil
+}
+
+// FieldResponse converts a http response to a zap response, which is compatible with the zap ObjectMarshaler interface.
+// A preview of the body is read, without consuming the body of 'resp'
+func FieldResponse(key string, resp *http.Response) zap.Field {
+	return zap.Object(key, NewResponse(resp))
+}
+
+// replayedBody replays data that has already been read, followed by the rest of the original body
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errorReader is an io.Reader that always returns 'err'
+type errorReader struct {
+	err error
+}
+
+// Read returns the error of the reader
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
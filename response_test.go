@@ -0,0 +1,91 @@
+package zerr
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponse(t *testing.T) {
+	resp := &http.Response{
+		Status:        "404 Not Found",
+		StatusCode:    http.StatusNotFound,
+		Header:        http.Header{"Content-Type": {"text/plain"}, "Set-Cookie": {"session=abc"}},
+		ContentLength: 9,
+		Body:          ioutil.NopCloser(strings.NewReader("not found")),
+		Request:       httptest.NewRequest("GET", "http://www.example.com/item?token=abc", nil),
+	}
+
+	// When
+	// we add the response to an error
+	e := WrapNoStack(nil).WithResponse(resp)
+
+	// Then
+	// the body should still be readable by the caller
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "not found", string(body))
+
+	// And
+	// the response should be encoded with status, headers, request and body
+	fields := e.Fields()
+	require.Len(t, fields, 1)
+	require.Equal(t, "response", fields[0].Key)
+
+	encoded := encodeObject(t, fields[0].Interface.(*Response))
+	require.Equal(t, "404 Not Found", encoded["Status"])
+	require.Equal(t, 404, encoded["StatusCode"])
+	require.Equal(t, int64(9), encoded["ContentLength"])
+	require.Equal(t, "not found", encoded["Body"])
+
+	header := encoded["Header"].(map[string]interface{})
+	require.Equal(t, []interface{}{"[REDACTED]"}, header["Set-Cookie"])
+
+	request := encoded["Request"].(map[string]interface{})
+	require.Equal(t, "GET", request["Method"])
+	require.Equal(t, "http://www.example.com/item?token=%5BREDACTED%5D", request["Url"])
+}
+
+// failingReader returns its data, followed by an error
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestResponseBodyReplay(t *testing.T) {
+	// When
+	// the body of a response fails partway through the preview
+	resp := &http.Response{Body: ioutil.NopCloser(&failingReader{data: []byte("hello")})}
+	NewResponse(resp)
+
+	// Then
+	// the data read before the failure, and the error, should be returned to the caller
+	body, err := ioutil.ReadAll(resp.Body)
+	require.EqualError(t, err, "connection reset")
+	require.Equal(t, "hello", string(body))
+
+	// When
+	// the body of a response is longer than the preview
+	long := strings.Repeat("x", DefaultBodyPolicy.MaxSize+10)
+	resp = &http.Response{Body: ioutil.NopCloser(strings.NewReader(long))}
+	NewResponse(resp)
+
+	// Then
+	// the complete body should be returned to the caller
+	body, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, long, string(body))
+}
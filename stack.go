@@ -0,0 +1,319 @@
+package zerr
+
+import (
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StackMode specifies how stacktraces are added to errors
+type StackMode int
+
+const (
+	// StackModeString adds the stacktrace as a single formatted string
+	StackModeString StackMode = iota
+
+	// StackModeFrames adds the stacktrace as an array of objects, one for each frame
+	StackModeFrames
+)
+
+// Frame describes a single frame of a stacktrace
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Package  string
+}
+
+// MarshalLogObject encodes the frame with a zapcore.ObjectEncoder
+func (f Frame) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("function", f.Function)
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+	enc.AddString("package", f.Package)
+	return nil
+}
+
+// frameArray is a list of frames that implements zapcore.ArrayMarshaler
+type frameArray []Frame
+
+// MarshalLogArray encodes each frame as an object
+func (a frameArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range a {
+		if err := enc.AppendObject(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stack holds the program counters of a stacktrace, as returned by runtime.Callers,
+// and the configuration used to format it
+type stack struct {
+	pcs    []uintptr
+	config *Config
+}
+
+// callers returns the stack of the caller, skipping the first 'skip' levels.
+// If frames may be omitted, more frames than MaxFrames are captured
+func callers(skip int, config *Config) *stack {
+	depth := config.maxFrames()
+	if config.filtersFrames() && depth < defaultMaxFrames {
+		depth = defaultMaxFrames
+	}
+
+	// The program counters are captured in a buffer on the stack, and only the frames
+	// that were actually captured are copied, since most stacks are much shorter than 'depth'
+	var buf [defaultMaxFrames]uintptr
+	pcs := buf[:]
+	if depth < len(buf) {
+		pcs = buf[:depth]
+	} else if depth > len(buf) {
+		pcs = make([]uintptr, depth)
+	}
+	n := runtime.Callers(skip+2, pcs)
+
+	s := &stack{pcs: make([]uintptr, n), config: config}
+	copy(s.pcs, pcs[:n])
+	return s
+}
+
+// frames resolves the program counters to frames, and applies the filters and depth limit of the configuration
+func (s *stack) frames() []Frame {
+	if len(s.pcs) == 0 {
+		return nil
+	}
+
+	config := s.config
+	maxFrames := config.maxFrames()
+	frames := make([]Frame, 0, len(s.pcs))
+	prevStdlib := false
+	iter := runtime.CallersFrames(s.pcs)
+	for more := true; more && len(frames) < maxFrames; {
+		var f runtime.Frame
+		f, more = iter.Next()
+		frame := Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+			Package:  packageName(f.Function),
+		}
+
+		if config.dropPackage(frame.Package) {
+			continue
+		}
+
+		stdlib := isStdlib(frame)
+		if config.CollapseStdlib && stdlib && prevStdlib {
+			continue
+		}
+
+		if config.FrameFilter != nil && !config.FrameFilter(frame) {
+			continue
+		}
+		prevStdlib = stdlib
+
+		if config.TrimPaths {
+			frame.File = frame.Package + "/" + path.Base(frame.File)
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// isStdlib reports whether the frame 'f' belongs to the standard library.
+// If the binary was built with GOROOT known, i.e. without -trimpath, frames are part of the standard library
+// if their source file is located in GOROOT. Otherwise, packages that belong to the main module or one of its
+// dependencies are not part of the standard library, and remaining packages are part of it if the first element
+// of their path does not contain a dot
+func isStdlib(f Frame) bool {
+	if f.Package == "" || f.Package == "main" {
+		return false
+	}
+	if goroot := gorootSrc(); goroot != "" {
+		return strings.HasPrefix(f.File, goroot)
+	}
+	if inModule(f.Package) {
+		return false
+	}
+	first := f.Package
+	if i := strings.Index(first, "/"); i >= 0 {
+		first = first[:i]
+	}
+	return !strings.Contains(first, ".")
+}
+
+var (
+	buildInfoOnce sync.Once
+	goroot        string
+	modules       []string
+)
+
+// loadBuildInfo reads the location of GOROOT and the modules of the binary
+func loadBuildInfo() {
+	buildInfoOnce.Do(func() {
+		// runtime.GOROOT returns an empty string if the binary was built with -trimpath
+		if root := runtime.GOROOT(); root != "" {
+			goroot = strings.TrimSuffix(filepath.ToSlash(root), "/") + "/src/"
+		}
+		if info, ok := debug.ReadBuildInfo(); ok {
+			if info.Main.Path != "" {
+				modules = append(modules, info.Main.Path)
+			}
+			for _, dep := range info.Deps {
+				modules = append(modules, dep.Path)
+			}
+		}
+	})
+}
+
+// gorootSrc returns the directory containing the source of the standard library, with a trailing slash,
+// or an empty string if it is unknown
+func gorootSrc() string {
+	loadBuildInfo()
+	return goroot
+}
+
+// inModule reports whether 'pkg' belongs to the main module or one of its dependencies
+func inModule(pkg string) bool {
+	loadBuildInfo()
+	for _, m := range modules {
+		if pkg == m || strings.HasPrefix(pkg, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// packageName returns the package path of a fully qualified function name, as reported by runtime.Frame,
+// e.g. "github.com/yzzyx/zerr" for "github.com/yzzyx/zerr.(*Error).Fields".
+// The package path ends at the first dot after the last slash. Dots in the last element of the path
+// are escaped by the compiler, e.g. "gopkg.in/yaml%2ev3.Marshal", and are restored in the returned path
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+	pkg := function
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		pkg = function[:slash+1+dot]
+	}
+	return strings.Replace(pkg, "%2e", ".", -1)
+}
+
+// String formats the stacktrace in the same way as zap.Stack
+func (s *stack) String() string {
+	var b strings.Builder
+	for i, f := range s.frames() {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(f.Function)
+		b.WriteString("\n\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+	}
+	return b.String()
+}
+
+// MarshalLogArray encodes each frame of the stacktrace as an object
+func (s *stack) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return frameArray(s.frames()).MarshalLogArray(enc)
+}
+
+// field returns the field used to log the stacktrace with the key 'key'.
+// The program counters are not resolved until the field is encoded, which means
+// that errors that are never logged do not pay for formatting the stacktrace
+func (s *stack) field(key string) zap.Field {
+	if s.config.StackMode == StackModeFrames {
+		return zap.Array(key, s)
+	}
+	return zap.Stringer(key, s)
+}
+
+// StackFrames returns the frames of the stacktrace captured when the error was wrapped,
+// or nil if no stacktrace is available
+func (e *Error) StackFrames() []Frame {
+	return StackFrames(e)
+}
+
+// StackFrames returns the frames of the first stacktrace found in the error chain,
+// or nil if no stacktrace is available
+func StackFrames(err error) []Frame {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			if e == nil {
+				return nil
+			}
+			if e.stack != nil {
+				return e.stack.frames()
+			}
+			err = e.err
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// foreignStack returns the program counters of the innermost stacktrace captured by another error package,
+// such as github.com/pkg/errors, or nil if no such stacktrace is available.
+// Errors implementing 'Callers() []uintptr', or 'StackTrace()' returning a slice of program counters
+// (like github.com/pkg/errors.StackTrace) are recognized
+func foreignStack(err error) []uintptr {
+	var pcs []uintptr
+	for err != nil {
+		if _, ok := err.(*Error); !ok {
+			if s := callersOf(err); s != nil {
+				pcs = s
+			}
+		}
+
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = u.Unwrap()
+	}
+	return pcs
+}
+
+// callersOf returns the program counters of the stacktrace attached to 'err', if any.
+// Nil pointers are skipped, since their methods may dereference the receiver
+func callersOf(err error) []uintptr {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		return c.Callers()
+	}
+
+	// github.com/pkg/errors returns its own type from StackTrace(), so we cannot
+	// check for it with a type assertion without importing the package
+	m := v.MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+	t := m.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := m.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
+}
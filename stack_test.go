@@ -0,0 +1,207 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestStackFrames(t *testing.T) {
+	// When
+	// we wrap an error without a stack
+	e := WrapNoStack(errors.New("test"))
+
+	// Then
+	// no frames are available
+	require.Nil(t, e.StackFrames())
+
+	// When
+	// we wrap an error with a stack
+	e = Wrap(errors.New("test"))
+
+	// Then
+	// the first frame should be the caller of Wrap, also through foreign wrappers
+	frames := StackFrames(fmt.Errorf("context: %w", e))
+	require.NotEmpty(t, frames)
+	require.Equal(t, "github.com/yzzyx/zerr.TestStackFrames", frames[0].Function)
+	require.Equal(t, "github.com/yzzyx/zerr", frames[0].Package)
+	require.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"))
+	require.NotZero(t, frames[0].Line)
+}
+
+func TestStackModeFrames(t *testing.T) {
+	config := NewConfig()
+	config.StackMode = StackModeFrames
+
+	// When
+	// we wrap an error with the stack mode set to frames
+	e := config.Wrap(errors.New("test"))
+
+	// Then
+	// the stacktrace should be added as an array of objects
+	fields := e.Fields()
+	require.Len(t, fields, 1)
+	enc := zapcore.NewMapObjectEncoder()
+	fields[0].AddTo(enc)
+
+	frames := enc.Fields["stacktrace"].([]interface{})
+	require.NotEmpty(t, frames)
+	frame := frames[0].(map[string]interface{})
+	require.Equal(t, "github.com/yzzyx/zerr.TestStackModeFrames", frame["function"])
+	require.Equal(t, "github.com/yzzyx/zerr", frame["package"])
+	require.Contains(t, frame, "file")
+	require.Contains(t, frame, "line")
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		function string
+		pkg      string
+	}{
+		{"main.main", "main"},
+		{"runtime.goexit", "runtime"},
+		{"net/http.(*conn).serve", "net/http"},
+		{"github.com/yzzyx/zerr.Wrap", "github.com/yzzyx/zerr"},
+		{"github.com/yzzyx/zerr.(*Error).Fields", "github.com/yzzyx/zerr"},
+		{"github.com/yzzyx/zerr.TestGo.func1.2", "github.com/yzzyx/zerr"},
+		{"gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3"},
+		{"gopkg.in/yaml%2ev3.(*encoder).marshalDoc", "gopkg.in/yaml.v3"},
+		{"gopkg.in/x%2ev3.T.Method.func1", "gopkg.in/x.v3"},
+		{"example.com/a.b/c%2ed.F", "example.com/a.b/c.d"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.pkg, packageName(tt.function), tt.function)
+	}
+}
+
+func TestIsStdlib(t *testing.T) {
+	loadBuildInfo()
+	savedGoroot, savedModules := goroot, modules
+	defer func() {
+		goroot, modules = savedGoroot, savedModules
+	}()
+
+	app := Frame{Package: "myapp/internal/db", File: "/home/user/myapp/internal/db/db.go"}
+	dep := Frame{Package: "github.com/pkg/errors", File: "/home/user/go/pkg/mod/github.com/pkg/errors/errors.go"}
+
+	// When
+	// the location of GOROOT is known
+	goroot, modules = "/usr/local/go/src/", nil
+
+	// Then
+	// only frames with source files in GOROOT are part of the standard library
+	require.True(t, isStdlib(Frame{Package: "net/http", File: "/usr/local/go/src/net/http/server.go"}))
+	require.False(t, isStdlib(app))
+	require.False(t, isStdlib(dep))
+	require.False(t, isStdlib(Frame{Package: "main", File: "/home/user/myapp/main.go"}))
+
+	// When
+	// the binary was built with -trimpath, so that GOROOT is unknown
+	goroot, modules = "", []string{"myapp", "github.com/pkg/errors"}
+	app.File = "myapp/internal/db/db.go"
+
+	// Then
+	// packages belonging to the modules of the binary are not part of the standard library
+	require.True(t, isStdlib(Frame{Package: "net/http", File: "net/http/server.go"}))
+	require.False(t, isStdlib(app))
+	require.False(t, isStdlib(dep))
+}
+
+var benchErr error
+
+// BenchmarkWrap measures the cost of wrapping an error, which only records the program counters
+func BenchmarkWrap(b *testing.B) {
+	err := errors.New("test")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = Wrap(err, zap.Int("intfield", i))
+	}
+}
+
+// BenchmarkWrapZapStackSkip measures the cost of wrapping an error with a stacktrace formatted by zap.StackSkip,
+// which was used before the stacktrace was formatted lazily
+func BenchmarkWrapZapStackSkip(b *testing.B) {
+	err := errors.New("test")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = WrapNoStack(err, zap.Int("intfield", i), zap.StackSkip("stacktrace", 1))
+	}
+}
+
+// BenchmarkWrapAndLog measures the cost of wrapping an error, and encoding it when logged
+func BenchmarkWrapAndLog(b *testing.B) {
+	err := errors.New("test")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range Wrap(err, zap.Int("intfield", i)).Fields() {
+			f.AddTo(enc)
+		}
+	}
+}
+
+// pkgError mimics the errors created by github.com/pkg/errors
+type pkgError struct {
+	msg   string
+	stack []uintptr
+}
+
+type pkgFrame uintptr
+type pkgStackTrace []pkgFrame
+
+func (e *pkgError) Error() string { return e.msg }
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	trace := make(pkgStackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		trace[i] = pkgFrame(pc)
+	}
+	return trace
+}
+
+// newPkgError creates an error with a stacktrace, in the same way as github.com/pkg/errors
+func newPkgError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+	return &pkgError{msg: msg, stack: pcs[:n]}
+}
+
+func TestForeignStack(t *testing.T) {
+	// When
+	// we wrap an error that already has a stacktrace from another package
+	err := fmt.Errorf("context: %w", newPkgError("test"))
+	e := Wrap(err, zap.Int("intfield", 1))
+
+	// Then
+	// the existing stacktrace should be used
+	frames := e.StackFrames()
+	require.NotEmpty(t, frames)
+	require.Equal(t, "github.com/yzzyx/zerr.newPkgError", frames[0].Function)
+
+	// And
+	// it should be added as the stacktrace field
+	fields := e.Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "stacktrace", fields[1].Key)
+	require.True(t, strings.HasPrefix(fields[1].Interface.(fmt.Stringer).String(), "github.com/yzzyx/zerr.newPkgError"))
+
+	// When
+	// the error is wrapped again
+	// Then
+	// no additional stacktrace is added
+	require.Len(t, Wrap(e, zap.Int("intfield", 2)).Fields(), 3)
+
+	// When
+	// we wrap a nil pointer of a type with a StackTrace method
+	// Then
+	// the method is not called
+	require.NotPanics(t, func() {
+		Wrap((*pkgError)(nil))
+	})
+}
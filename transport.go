@@ -0,0 +1,73 @@
+package zerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Transport is a http.RoundTripper that wraps transport errors with information about the request,
+// the duration of the attempt and a stacktrace.
+// If IsError is set, responses for which it returns true are also converted to errors,
+// which include the response, and the error code corresponding to the status code
+//
+//	client := &http.Client{Transport: &zerr.Transport{IsError: zerr.IsErrorStatus}}
+type Transport struct {
+	// Base is the RoundTripper used to perform the requests.
+	// If nil, http.DefaultTransport is used
+	Base http.RoundTripper
+
+	// IsError reports whether a response should be converted to an error.
+	// If nil, only transport errors are returned. It should not match redirects (3xx) if the transport
+	// is used by a http.Client that follows redirects, since the client then receives the error instead
+	// of following the redirect
+	IsError func(resp *http.Response) bool
+}
+
+// IsErrorStatus reports whether the status code of 'resp' is a client or server error (4xx or 5xx).
+// It can be used as Transport.IsError. Other status codes, such as redirects and protocol upgrades,
+// are not errors, since an error returned by the transport stops http.Client from handling them
+func IsErrorStatus(resp *http.Response) bool {
+	return resp.StatusCode >= 400
+}
+
+// RoundTrip executes a single HTTP transaction with the base RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		return nil, wrapWithStack(1, err, FieldRequest("request", req), zap.Duration("duration", duration))
+	}
+
+	if t.IsError == nil || !t.IsError(resp) {
+		return resp, nil
+	}
+
+	// The response is read before closing the body, so that a preview of the body is included
+	responseField := FieldResponse("response", resp)
+	resp.Body.Close()
+
+	msg := fmt.Sprintf("%s %s: unexpected response status %s", req.Method, DefaultRedactionPolicy.URL(req.URL), resp.Status)
+	e := wrapWithStack(1, errors.New(msg), FieldRequest("request", req), responseField, zap.Duration("duration", duration))
+	if c := codeForStatus(resp.StatusCode); c != CodeUnknown {
+		e = e.WithCode(c)
+	}
+	return nil, e
+}
+
+// codeForStatus returns the error code that corresponds to a HTTP status code, or CodeUnknown if none is available
+func codeForStatus(status int) Code {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	return statusCodes[status]
+}
@@ -0,0 +1,107 @@
+package zerr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "no such item", http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/item", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{IsError: IsErrorStatus}}
+
+	// When
+	// a request succeeds
+	resp, err := client.Get(server.URL + "/item")
+
+	// Then
+	// the response is returned as usual
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// When
+	// a request is redirected
+	resp, err = client.Get(server.URL + "/moved")
+
+	// Then
+	// the redirect is followed
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/item", resp.Request.URL.Path)
+	resp.Body.Close()
+
+	// When
+	// a request returns an error status
+	_, err = client.Get(server.URL + "/missing")
+
+	// Then
+	// an error with the request, response, duration, code and stacktrace is returned
+	require.Error(t, err)
+	require.True(t, errors.Is(err, CodeNotFound))
+	keys := map[string]bool{}
+	for _, f := range Fields(err) {
+		keys[f.Key] = true
+	}
+	require.Equal(t, map[string]bool{
+		"request":    true,
+		"response":   true,
+		"duration":   true,
+		"stacktrace": true,
+		"code":       true,
+	}, keys)
+
+	// When
+	// the request fails in the transport
+	server.Close()
+	_, err = client.Get(server.URL + "/item")
+
+	// Then
+	// the error is wrapped with the request and duration
+	require.Error(t, err)
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	fields := Fields(err)
+	require.Len(t, fields, 3)
+	require.Equal(t, "request", fields[0].Key)
+	require.Equal(t, "duration", fields[1].Key)
+	require.Equal(t, "stacktrace", fields[2].Key)
+}
+
+func TestCodeForStatus(t *testing.T) {
+	restoreStatus(t)
+
+	// When
+	// another code is registered for a status that already has a code
+	RegisterStatus(Code("missing"), http.StatusNotFound)
+
+	// Then
+	// the first code should always be returned
+	for i := 0; i < 100; i++ {
+		require.Equal(t, CodeNotFound, codeForStatus(http.StatusNotFound))
+	}
+
+	// When
+	// a code is moved to another status
+	RegisterStatus(CodeUnavailable, http.StatusBadGateway)
+
+	// Then
+	// the code should only be returned for the new status
+	require.Equal(t, CodeUnavailable, codeForStatus(http.StatusBadGateway))
+	require.Equal(t, CodeUnknown, codeForStatus(http.StatusServiceUnavailable))
+}
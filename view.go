@@ -0,0 +1,67 @@
+package zerr
+
+import (
+	"go.uber.org/zap"
+)
+
+// Without returns a new Error that excludes the fields with the given keys, including fields
+// added by zerr such as "stacktrace" and "code". The original error is not modified,
+// and the returned error still wraps it, so that errors.Is and errors.As work as before.
+// Fields added to the returned error are not affected
+func (e *Error) Without(keys ...string) *Error {
+	exclude := keySet(keys)
+	return e.withView(func(fields []zap.Field) []zap.Field {
+		filtered := make([]zap.Field, 0, len(fields))
+		for _, f := range fields {
+			if !exclude[f.Key] {
+				filtered = append(filtered, f)
+			}
+		}
+		return filtered
+	})
+}
+
+// Only returns a new Error that only includes the fields with the given keys.
+// See Without for details
+func (e *Error) Only(keys ...string) *Error {
+	include := keySet(keys)
+	return e.withView(func(fields []zap.Field) []zap.Field {
+		filtered := make([]zap.Field, 0, len(keys))
+		for _, f := range fields {
+			if include[f.Key] {
+				filtered = append(filtered, f)
+			}
+		}
+		return filtered
+	})
+}
+
+// Namespaced returns a new Error where all fields are added as an object with the key 'namespace'.
+// See Without for details
+func (e *Error) Namespaced(namespace string) *Error {
+	return e.withView(func(fields []zap.Field) []zap.Field {
+		if len(fields) == 0 {
+			return nil
+		}
+		return []zap.Field{zap.Object(namespace, fieldList(fields))}
+	})
+}
+
+// withView creates a new Error instance, where the fields of 'e' are passed through 'view'
+func (e *Error) withView(view func([]zap.Field) []zap.Field) *Error {
+	return &Error{
+		err:      e,
+		view:     view,
+		config:   e.config,
+		hasStack: e.hasStack,
+	}
+}
+
+// keySet converts a list of keys to a set
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
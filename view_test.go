@@ -0,0 +1,63 @@
+package zerr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestViews(t *testing.T) {
+	original := errors.New("test")
+	e := WrapCode(original, CodeNotFound, zap.Int("user_id", 1), zap.String("query", "select"))
+
+	keys := func(fields []zap.Field) []string {
+		var keys []string
+		for _, f := range fields {
+			keys = append(keys, f.Key)
+		}
+		return keys
+	}
+
+	// When
+	// fields are excluded
+	without := e.Without("query", "stacktrace")
+
+	// Then
+	// the remaining fields are returned
+	require.Equal(t, []string{"user_id", "code"}, keys(without.Fields()))
+
+	// And
+	// the original error is not modified, and can still be found
+	require.Equal(t, []string{"user_id", "query", "stacktrace", "code"}, keys(e.Fields()))
+	require.True(t, errors.Is(without, original))
+	require.True(t, errors.Is(without, CodeNotFound))
+
+	// When
+	// fields are added to the view
+	// Then
+	// they are not affected by it
+	require.Equal(t, []string{"query", "user_id", "code"}, keys(without.WithString("query", "outer").Fields()))
+
+	// When
+	// only specific fields are included
+	// Then
+	// all other fields, including the code, are excluded
+	require.Equal(t, []string{"user_id"}, keys(e.Only("user_id").Fields()))
+	_, ok := Lookup(e.Only("user_id"), "query")
+	require.False(t, ok)
+
+	// When
+	// the fields are namespaced
+	namespaced := e.Only("user_id", "query").Namespaced("details")
+
+	// Then
+	// they are added as an object
+	fields := namespaced.Fields()
+	require.Equal(t, []string{"details"}, keys(fields))
+	enc := zapcore.NewMapObjectEncoder()
+	fields[0].AddTo(enc)
+	require.Equal(t, map[string]interface{}{"user_id": int64(1), "query": "select"}, enc.Fields["details"])
+}
@@ -8,35 +8,50 @@ import (
 	"go.uber.org/zap"
 )
 
-// Error is the type used to wrap other errors with additional fields
+// Error is the type used to wrap other errors with additional fields.
+// An Error is immutable once created: all methods adding information return a new instance,
+// and the slice of fields is never modified or appended to. This makes it safe to share
+// errors, and to derive new errors from them, across goroutines
 type Error struct {
 	err      error
+	msg      string
 	fields   []zap.Field
+	code     Code
+	stack    *stack
+	origin   *stack
+	crumb    *breadcrumb
+	view     func([]zap.Field) []zap.Field
+	config   *Config
 	hasStack bool
 }
 
-// Error makes us implement the standard error interface
+// Error makes us implement the standard error interface.
+// If the error was created with a message, e.g. by WrapMsg, the message is prepended to the message of the wrapped error
 func (e *Error) Error() string {
-	if e == nil || e.err == nil {
+	if e == nil {
+		return ""
+	}
+	if e.msg != "" {
+		if e.err == nil {
+			return e.msg
+		}
+		return e.msg + ": " + e.err.Error()
+	}
+	if e.err == nil {
 		return ""
 	}
 	return e.err.Error()
 }
 
-// Fields returns all fields attached to this error, and all fields attached to previous errors
+// Fields returns all fields attached to this error, and all fields attached to previous errors.
+// Errors that are not of type *Error (e.g. created by fmt.Errorf("%w") or errors.Join) are
+// traversed as well, so that fields attached further down the chain are not lost.
+// If an error code has been set, it is included as the field "code"
 func (e *Error) Fields() []zap.Field {
-	var ok bool
-	fields := e.fields
-
-	err := e
-	for {
-		err, ok = err.err.(*Error)
-		if !ok {
-			break
-		}
-		fields = append(fields, err.fields...)
+	if e == nil {
+		return nil
 	}
-	return fields
+	return Fields(e)
 }
 
 // Unwrap returns the cause of this error
@@ -54,15 +69,41 @@ func (e *Error) Cause() error {
 // WithField creates a new Error instance, with one or more fields added.
 // Note that this is equivalent to calling WrapNoStack(err, f)
 func (e *Error) WithField(f zap.Field, additionalFields ...zap.Field) *Error {
+	// Appending to 'additionalFields' could modify the caller's slice, so a new one is allocated
+	fields := make([]zap.Field, 0, len(additionalFields)+1)
+	fields = append(fields, additionalFields...)
+	fields = append(fields, f)
+	return e.with(1, fields)
+}
+
+// withField is used by the WithX methods, so that breadcrumbs point to their caller
+func (e *Error) withField(f zap.Field) *Error {
+	return e.with(2, []zap.Field{f})
+}
+
+// with creates a new Error instance wrapping 'e', with the fields 'fields'.
+// If breadcrumbs are enabled, the caller is recorded, skipping the first 'skip' levels
+func (e *Error) with(skip int, fields []zap.Field) *Error {
 	newErr := &Error{
 		err:      e,
-		fields:   append(additionalFields, f),
+		fields:   fields,
+		config:   e.config,
 		hasStack: e.hasStack,
 	}
-
+	if e.conf().Breadcrumbs {
+		newErr.crumb = &breadcrumb{pc: callerPC(skip + 1)}
+	}
 	return newErr
 }
 
+// conf returns the configuration used when the error was wrapped, or the global configuration
+func (e *Error) conf() *Config {
+	if e.config == nil {
+		return DefaultConfig()
+	}
+	return e.config
+}
+
 // LogDebug logs an Error with Debug level to a given zap logger
 func (e *Error) LogDebug(logger *zap.Logger) {
 	if e == nil {
@@ -150,7 +191,8 @@ func (e *Error) LogFatal(logger *zap.Logger) {
 	logger.Fatal(e.Error(), e.Fields()...)
 }
 
-// Wrap adds zap fields to an error
+// Wrap adds zap fields to an error.
+// A stacktrace is added, unless the error already has one or stacktraces are disabled by the global configuration
 func Wrap(err error, fields ...zap.Field) *Error {
 	return wrapWithStack(1, err, fields...)
 }
@@ -159,38 +201,104 @@ func Wrap(err error, fields ...zap.Field) *Error {
 // This function allows us to remove any 'zerr' calls from the stacktraces, and instead
 // list the stacktrace of the original caller
 func wrapWithStack(lvl int, err error, fields ...zap.Field) *Error {
+	return DefaultConfig().wrapWithStack(lvl+1, err, fields...)
+}
+
+// wrapWithStack wraps the error and attaches a stacktrace according to the configuration 'c'
+func (c *Config) wrapWithStack(lvl int, err error, fields ...zap.Field) *Error {
+	return c.wrapWithMessage(lvl+1, err, "", fields...)
+}
+
+// wrapWithMessage wraps the error with a message, and attaches a stacktrace according to the configuration 'c'
+func (c *Config) wrapWithMessage(lvl int, err error, msg string, fields ...zap.Field) *Error {
 	// If we're not adding any fields, and the supplied error is already of the correct type,
-	// return it directly
-	if e, ok := err.(*Error); ok && len(fields) == 0 {
+	// return it directly, unless we need to record a breadcrumb or a message
+	if e, ok := err.(*Error); ok && len(fields) == 0 && msg == "" && !c.Breadcrumbs {
 		return e
 	}
 
 	// Check if we've already wrapped with a stack.
 	// If that's the case, we won't add another stacktrace
-	var e *Error
 	hasStack := false
-	if errors.As(err, &e) {
+	if e := outermostError(err); e != nil {
 		hasStack = e.hasStack
 	}
 
+	// If the error already has a stacktrace captured by another package, e.g. github.com/pkg/errors,
+	// it is reused, since it is more accurate than the one we would capture
+	var s *stack
 	if !hasStack {
-		fields = append(fields, zap.StackSkip("stacktrace", lvl+1))
+		if pcs := foreignStack(err); pcs != nil {
+			s = &stack{pcs: pcs, config: c}
+			hasStack = true
+		}
 	}
 
-	return &Error{
+	if !hasStack && !c.AddStacktrace {
+		newErr := c.wrapNoStack(lvl+1, err, fields...)
+		newErr.msg = msg
+		return newErr
+	}
+
+	// Only the program counters are recorded here - the stacktrace is formatted when the error is logged
+	if !hasStack {
+		s = callers(lvl+1, c)
+	}
+
+	newErr := &Error{
 		err:      err,
-		fields:   fields,
+		msg:      msg,
+		fields:   copyFields(fields),
+		stack:    s,
+		config:   c,
 		hasStack: true,
 	}
+	if c.Breadcrumbs {
+		newErr.crumb = &breadcrumb{pc: callerPC(lvl + 1)}
+	}
+	return newErr
+}
+
+// WrapMsg adds a message and zap fields to an error. The message is prepended to the error message,
+// e.g. "loading config: open config.yaml: no such file or directory", and the error can still be
+// unwrapped. A stacktrace is added in the same way as for Wrap
+func WrapMsg(err error, msg string, fields ...zap.Field) *Error {
+	return DefaultConfig().wrapWithMessage(1, err, msg, fields...)
+}
+
+// Wrapf adds a formatted message to an error. See WrapMsg for details
+func Wrapf(err error, format string, args ...interface{}) *Error {
+	return DefaultConfig().wrapWithMessage(1, err, fmt.Sprintf(format, args...))
+}
+
+// New creates a new error with the message 'msg' and zap fields, and a stacktrace
+func New(msg string, fields ...zap.Field) *Error {
+	return DefaultConfig().wrapWithStack(1, errors.New(msg), fields...)
+}
+
+// Errorf creates a new error with a formatted message, and a stacktrace.
+// The format is handled by fmt.Errorf, so errors can be wrapped with %w
+func Errorf(format string, args ...interface{}) *Error {
+	return DefaultConfig().wrapWithStack(1, fmt.Errorf(format, args...))
 }
 
 // WrapNoStack wraps error with fields, but always excludes the stack trace
 func WrapNoStack(err error, fields ...zap.Field) *Error {
-	// Tag that this error has a stacktrace
-	return &Error{
+	return DefaultConfig().wrapNoStack(1, err, fields...)
+}
+
+// wrapNoStack wraps the error without a stacktrace. If breadcrumbs are enabled,
+// the caller is recorded, skipping the first 'lvl' levels
+func (c *Config) wrapNoStack(lvl int, err error, fields ...zap.Field) *Error {
+	newErr := &Error{
 		err:    err,
-		fields: fields,
+		fields: copyFields(fields),
+		config: c,
+	}
+	if c.Breadcrumbs {
+		newErr.crumb = &breadcrumb{pc: callerPC(lvl + 1)}
 	}
+	return newErr
 }
 
 // Sugar is a sugared version of the 'Wrap'  function above.
@@ -204,13 +312,152 @@ func Sugar(err error, args ...interface{}) *Error {
 // SugarNoStack is exactly like the 'Sugar' function but without an additional stacktrace
 func SugarNoStack(err error, args ...interface{}) *Error {
 	fields := sugarFields(args...)
-	return WrapNoStack(err, fields...)
+	return DefaultConfig().wrapNoStack(1, err, fields...)
 }
 
-// Fields returns any/all fields that are attached to an error
+// copyFields returns a copy of 'fields', so that the error is not affected if the caller
+// modifies its slice afterwards. The copy has no spare capacity, so appending to it always
+// allocates a new array instead of writing to one that may be shared
+func copyFields(fields []zap.Field) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	c := make([]zap.Field, len(fields))
+	copy(c, fields)
+	return c
+}
+
+// Fields returns any/all fields that are attached to an error, or to any error it wraps.
+// If more than one error in the chain has a field with the same key, the conflict is
+// resolved according to the FieldConflict setting of the configuration.
+// The returned slice is newly allocated, and may be modified by the caller
 func Fields(err error) []zap.Field {
-	if e, ok := err.(*Error); ok {
-		return e.Fields()
+	// Most chains are short, so the layers are collected in a buffer on the stack
+	var buf [16]layer
+	return resolveFields(err, collectLayers(buf[:0], err))
+}
+
+// viewFields returns the fields of the error wrapped by 'e', passed through the view of 'e'.
+// This is kept separate from Fields, since views are resolved recursively by collectLayers,
+// which causes the buffer to be allocated on the heap. That way, only errors with views pay for it
+func viewFields(e *Error) []zap.Field {
+	var buf [16]layer
+	return e.view(resolveFields(e.err, collectLayers(buf[:0], e.err)))
+}
+
+// resolveFields returns the fields of 'layers', collected from 'err', together with the code and trace of 'err'
+func resolveFields(err error, layers []layer) []zap.Field {
+	var extra [2]zap.Field
+	n := 0
+	if f, ok := codeField(err); ok {
+		extra[n] = f
+		n++
+	}
+	if f, ok := traceField(err); ok {
+		extra[n] = f
+		n++
+	}
+	return configOf(err).FieldConflict.flatten(layers, extra[:n])
+}
+
+// layer holds the fields added by a single error in the chain
+type layer struct {
+	// fields are the fields added by the user. These may conflict with the fields of other layers
+	fields []zap.Field
+
+	// e is the error the layer belongs to. Its stacktraces are always added as they are
+	e *Error
+
+	// multi is set if the layer consists of errors created by Join
+	multi *multiError
+}
+
+// size returns the number of fields of the layer, when not namespaced
+func (l *layer) size() int {
+	n := len(l.fields)
+	if l.multi != nil {
+		n++
+	}
+	if l.e != nil && l.e.stack != nil {
+		n++
+	}
+	if l.e != nil && l.e.origin != nil {
+		n++
+	}
+	return n
+}
+
+// appendInternal appends the fields that are added by zerr itself, such as stacktraces
+func (l *layer) appendInternal(fields []zap.Field) []zap.Field {
+	if l.multi != nil {
+		fields = append(fields, l.multi.field())
+	}
+	if l.e != nil && l.e.stack != nil {
+		fields = append(fields, l.e.stack.field(l.e.stack.config.stackKey()))
+	}
+	if l.e != nil && l.e.origin != nil {
+		fields = append(fields, l.e.origin.field(l.e.origin.config.originKey()))
+	}
+	return fields
+}
+
+// collectLayers walks the tree of wrapped errors depth-first, and appends
+// a layer for every *Error found to 'layers'.
+// Both 'Unwrap() error' and 'Unwrap() []error' are followed, except for errors
+// created by Join, which are added as a single layer.
+func collectLayers(layers []layer, err error) []layer {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			if e == nil {
+				return layers
+			}
+			if e.view != nil {
+				// All fields of the wrapped errors are passed through the view, and added as a single layer
+				return append(layers, layer{fields: viewFields(e)})
+			}
+			if len(e.fields) > 0 || e.stack != nil || e.origin != nil {
+				layers = append(layers, layer{fields: e.fields, e: e})
+			}
+			err = e.err
+		case *multiError:
+			// Errors joined by us keep their fields separate
+			return append(layers, layer{multi: e})
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				layers = collectLayers(layers, child)
+			}
+			return layers
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return layers
+		}
+	}
+	return layers
+}
+
+// configOf returns the configuration of the outermost *Error in the chain,
+// or the global configuration if there is none
+func configOf(err error) *Config {
+	if e := outermostError(err); e != nil {
+		return e.conf()
+	}
+	return DefaultConfig()
+}
+
+// outermostError returns the outermost *Error in the chain, or nil if there is none.
+// Unlike errors.As, it does not require the target to be allocated on the heap
+func outermostError(err error) *Error {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return e
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return nil
+		}
 	}
 	return nil
 }
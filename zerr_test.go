@@ -2,7 +2,9 @@ package zerr
 
 import (
 	"errors"
+	"fmt"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -69,6 +71,48 @@ func TestWrap(t *testing.T) {
 	}
 }
 
+// joinedError mimics errors.Join, which is not available in all supported Go versions
+type joinedError []error
+
+func (e joinedError) Error() string   { return "joined" }
+func (e joinedError) Unwrap() []error { return e }
+
+func TestFieldsForeignWrappers(t *testing.T) {
+	err := errors.New("test")
+
+	// When
+	// a wrapped error is wrapped again with fmt.Errorf
+	err = WrapNoStack(err, zap.Int("intfield", 1))
+	err = fmt.Errorf("context: %w", err)
+
+	// Then
+	// the field should still be available
+	fields := Fields(err)
+	require.Len(t, fields, 1)
+	require.Equal(t, "intfield", fields[0].Key)
+
+	// When
+	// the error is wrapped again, both by zerr and fmt.Errorf
+	err = fmt.Errorf("more context: %w", WrapNoStack(err, zap.String("stringfield", "abc")))
+
+	// Then
+	// all fields should be returned, outermost first
+	fields = Fields(err)
+	require.Len(t, fields, 2)
+	require.Equal(t, "stringfield", fields[0].Key)
+	require.Equal(t, "intfield", fields[1].Key)
+
+	// When
+	// multiple errors are joined by a type implementing 'Unwrap() []error', like errors.Join
+	var joined error = joinedError{err, WrapNoStack(errors.New("other"), zap.Bool("boolfield", true))}
+
+	// Then
+	// fields from all branches should be returned
+	fields = WrapNoStack(joined).Fields()
+	require.Len(t, fields, 3)
+	require.Equal(t, "boolfield", fields[2].Key)
+}
+
 func TestCause(t *testing.T) {
 	// When
 	// we wrap an error
@@ -222,3 +266,126 @@ func TestNil(t *testing.T) {
 	logger = zap.NewNop()
 	e.LogError(logger)
 }
+
+func TestFieldsAliasing(t *testing.T) {
+	// When
+	// two errors are derived from the same parent, using a shared slice with spare capacity
+	shared := make([]zap.Field, 1, 10)
+	shared[0] = zap.Int("shared", 1)
+	parent := WrapNoStack(errors.New("test"), shared...)
+	e1 := parent.WithField(zap.String("child", "one"), shared...)
+	e2 := parent.WithField(zap.String("child", "two"), shared...)
+
+	// Then
+	// the fields of each error should not be affected by the other
+	require.Equal(t, "one", e1.Fields()[1].String)
+	require.Equal(t, "two", e2.Fields()[1].String)
+
+	// When
+	// the fields of an error are modified by the caller
+	fields := parent.Fields()
+	fields[0] = zap.Int("modified", 2)
+	_ = append(fields[:0], zap.Int("appended", 3))
+
+	// Then
+	// the error should not be affected
+	require.Equal(t, "shared", parent.Fields()[0].Key)
+
+	// When
+	// the slice passed when wrapping is modified by the caller
+	shared[0] = zap.Int("modified", 2)
+
+	// Then
+	// the error should not be affected
+	require.Equal(t, "shared", parent.Fields()[0].Key)
+	shared[0] = zap.Int("shared", 1)
+
+	// When
+	// errors are derived from the same parent, and read, concurrently
+	// (run with -race to detect data races)
+	results := make([][]zap.Field, 16)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := parent.WithInt("child", i).WithField(zap.Int("grandchild", i), shared...)
+			results[i] = e.Fields()
+		}(i)
+	}
+	wg.Wait()
+
+	// Then
+	// each derived error should contain its own fields
+	for i, fields := range results {
+		require.Len(t, fields, 4)
+		require.Equal(t, int64(i), fields[1].Integer)
+		require.Equal(t, int64(i), fields[2].Integer)
+		require.Equal(t, "shared", fields[3].Key)
+	}
+}
+
+// BenchmarkWithField measures the cost of adding fields to an error
+func BenchmarkWithField(b *testing.B) {
+	e := WrapNoStack(errors.New("test"), zap.Int("intfield", 1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchErr = e.WithField(zap.Int("intfield", i), zap.String("stringfield", "abc"))
+	}
+}
+
+// BenchmarkFields measures the cost of collecting the fields of an error wrapped in multiple layers
+func BenchmarkFields(b *testing.B) {
+	e := WrapNoStack(errors.New("test"), zap.Int("intfield", 1))
+	for i := 0; i < 5; i++ {
+		e = e.WithInt("intfield", i).WithString("stringfield", "abc")
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = e.Fields()
+	}
+}
+
+func TestMessages(t *testing.T) {
+	// When
+	// we create a new error
+	e := New("something failed", zap.Int("intfield", 1))
+
+	// Then
+	// it should have the message, field and a stacktrace
+	require.Equal(t, "something failed", e.Error())
+	require.Len(t, e.Fields(), 2)
+	require.Equal(t, "github.com/yzzyx/zerr.TestMessages", e.StackFrames()[0].Function)
+
+	// When
+	// we create a formatted error wrapping another error
+	original := errors.New("original error")
+	e = Errorf("request %d: %w", 1, original)
+
+	// Then
+	// the message should be formatted, and the original error reachable
+	require.Equal(t, "request 1: original error", e.Error())
+	require.True(t, errors.Is(e, original))
+	require.Equal(t, "github.com/yzzyx/zerr.TestMessages", e.StackFrames()[0].Function)
+
+	// When
+	// we wrap an error with a message
+	e = WrapMsg(original, "loading config", zap.String("filename", "config.yaml"))
+
+	// Then
+	// the message should be prepended, and the original error reachable
+	require.Equal(t, "loading config: original error", e.Error())
+	require.Equal(t, original, e.Unwrap())
+	require.Len(t, e.Fields(), 2)
+
+	// When
+	// we wrap it again with a formatted message
+	e = Wrapf(e, "starting %s", "server")
+
+	// Then
+	// both messages should be prepended, and no additional stacktrace added
+	require.Equal(t, "starting server: loading config: original error", e.Error())
+	require.Len(t, e.Fields(), 2)
+	require.Equal(t, original, Cause(e))
+}